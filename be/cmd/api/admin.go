@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"prayerreq-backend/internal/config"
+	userData "prayerreq-backend/internal/controller/user/data"
+	userRepo "prayerreq-backend/internal/controller/user/repository"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// promoteAdmin implements the promote-admin command, which bootstraps the
+// first administrator; later ones can be appointed through the API:
+//
+//	api promote-admin <email>   give the account with this email the admin role
+func promoteAdmin(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: %s promote-admin <email>", os.Args[0])
+	}
+	email := strings.ToLower(strings.TrimSpace(args[0]))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.Storage != config.StorageMongo {
+		return errors.New("promote-admin requires STORAGE_DRIVER=mongo")
+	}
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDatabase(db)
+
+	users := userRepo.NewMongoRepository(db.Database)
+	user, err := users.GetUserByEmail(ctx, email)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("no account with email %q; register it first", email)
+	}
+	if err != nil {
+		return err
+	}
+
+	if user.Role == userData.RoleAdmin {
+		fmt.Printf("%s is already an administrator\n", email)
+		return nil
+	}
+
+	user.Role = userData.RoleAdmin
+	user.UpdatedAt = time.Now()
+	if err := users.UpdateUser(ctx, user.ID.Hex(), user); err != nil {
+		return err
+	}
+
+	fmt.Printf("Promoted %s to administrator\n", email)
+	return nil
+}
@@ -2,69 +2,199 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"fmt"
 	"log"
+	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"prayerreq-backend/internal/auth"
+	"prayerreq-backend/internal/config"
+	"prayerreq-backend/internal/controller/moderation"
+	"prayerreq-backend/internal/controller/notification"
+	notificationRepo "prayerreq-backend/internal/controller/notification/repository"
 	"prayerreq-backend/internal/controller/prayer"
 	prayerRepo "prayerreq-backend/internal/controller/prayer/repository"
+	"prayerreq-backend/internal/controller/trip"
+	tripRepo "prayerreq-backend/internal/controller/trip/repository"
 	"prayerreq-backend/internal/controller/user"
 	userRepo "prayerreq-backend/internal/controller/user/repository"
 	"prayerreq-backend/internal/database"
+	"prayerreq-backend/internal/events"
+	moderationFilter "prayerreq-backend/internal/moderation"
+	"prayerreq-backend/internal/scheduler"
 	"prayerreq-backend/internal/server"
 )
 
 func main() {
-	// Database configuration
-	mongoURI := os.Getenv("MONGODB_URI")
-	if mongoURI == "" {
-		mongoURI = "mongodb://localhost:27017"
+	command := "serve"
+	if len(os.Args) > 1 {
+		command = os.Args[1]
 	}
 
-	dbName := os.Getenv("DB_NAME")
-	if dbName == "" {
-		dbName = "prayerreq"
+	var err error
+	switch command {
+	case "serve":
+		err = run()
+	case "migrate":
+		err = migrate(os.Args[2:])
+	case "promote-admin":
+		err = promoteAdmin(os.Args[2:])
+	case "repair":
+		err = repair(os.Args[2:])
+	default:
+		err = fmt.Errorf("unknown command %q (expected serve, migrate, promote-admin or repair)", command)
 	}
-
-	// Initialize database connection
-	db, err := database.New(mongoURI, dbName)
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		log.Fatal(err)
 	}
-	defer func() {
-		if err := db.Close(context.Background()); err != nil {
-			log.Printf("Error closing database: %v", err)
-		}
-	}()
+}
 
-	log.Println("Connected to MongoDB successfully")
+// run wires up the application and serves until SIGINT or SIGTERM. Cleanup
+// happens in reverse order through defers: the server drains first, then
+// the database connection is closed.
+func run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	slog.SetLogLoggerLevel(cfg.SlogLevel())
+	log.Printf("Starting in %s mode", cfg.Environment)
 
 	// Initialize repositories
 	var (
+		prayerRepository       prayerRepo.Repository
+		userRepository         userRepo.Repository
+		tripRepository         tripRepo.Repository
+		notificationRepository notificationRepo.Repository
+	)
+
+	switch cfg.Storage {
+	case config.StorageMemory:
+		log.Println("Using in-memory storage; data will not survive a restart")
+		prayerRepository = prayerRepo.NewMemoryRepository()
+		userRepository = userRepo.NewMemoryRepository()
+		tripRepository = tripRepo.NewMemoryRepository()
+		notificationRepository = notificationRepo.NewMemoryRepository()
+	case config.StorageMongo:
+		// Initialize database connection
+		db, err := openDatabase(cfg)
+		if err != nil {
+			return err
+		}
+		defer closeDatabase(db)
+
+		if cfg.Database.AutoMigrate {
+			applied, err := db.Migrate(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to migrate database: %w", err)
+			}
+			log.Printf("Database schema up to date (%d migrations applied)", applied)
+		}
+
 		prayerRepository = prayerRepo.NewMongoRepository(db.Database)
-		userRepository   = userRepo.NewMongoRepository(db.Database)
+		userRepository = userRepo.NewMongoRepository(db.Database)
+		tripRepository = tripRepo.NewMongoRepository(db.Database)
+		notificationRepository = notificationRepo.NewMongoRepository(db.Database)
+	}
+
+	// Authentication; production refuses to start without a secret
+	secret := []byte(cfg.Auth.Secret)
+	if len(secret) == 0 {
+		log.Println("AUTH_SECRET is not set; using a random secret, sessions will not survive a restart")
+		secret = make([]byte, 32)
+		rand.Read(secret)
+	}
+
+	var (
+		tokens        = auth.NewTokenManager(secret, cfg.Auth.AccessTokenTTL, cfg.Auth.RefreshTokenTTL)
+		authenticator = auth.NewAuthenticator(tokens, userRepository)
 	)
 
+	// Throttling of write endpoints, per client
+	limiter := server.NewRateLimiter(server.NewMemoryRateLimitStore(), cfg.RateLimit)
+
+	// Live update broker for the prayer stream
+	broker := events.NewBroker(1000)
+
+	// Screening of new prayer requests and comments
+	moderator, err := moderationFilter.New(cfg.Moderation)
+	if err != nil {
+		return err
+	}
+
 	// Initialize services
 	var (
-		prayerService = prayer.NewService(prayerRepository)
-		userService   = user.NewService(userRepository)
+		notificationService = notification.NewService(notificationRepository)
+		prayerService       = prayer.NewService(prayerRepository, broker, notificationService, moderator, cfg.Features)
+		userService         = user.NewService(userRepository, tokens, prayerRepository, cfg.Features)
+		tripService         = trip.NewService(tripRepository)
+		moderationService   = moderation.NewService(prayerService, userRepository)
 	)
 
 	// Initialize HTTP handlers
 	var (
-		prayerHandler = prayer.NewHTTPHandler(prayerService)
-		userHandler   = user.NewHTTPHandler(userService)
+		prayerHandler       = prayer.NewHTTPHandler(prayerService)
+		userHandler         = user.NewHTTPHandler(userService)
+		tripHandler         = trip.NewHTTPHandler(tripService)
+		notificationHandler = notification.NewHTTPHandler(notificationService)
+		moderationHandler   = moderation.NewHTTPHandler(moderationService)
 	)
 
-	// Initialize server
-	srv := server.New(prayerHandler, userHandler)
+	// Background jobs stop with the server; the deferred Wait runs before
+	// the database is closed
+	jobs := scheduler.New()
+	jobs.Every("purge-trash", cfg.Trash.PurgeInterval, func(ctx context.Context) error {
+		purged, err := prayerService.PurgeTrash(ctx, cfg.Trash.Retention)
+		if purged > 0 {
+			log.Printf("Purged %d prayer requests from the trash", purged)
+		}
+		return err
+	})
+	jobs.Every("archive-expired", cfg.Archive.Interval, func(ctx context.Context) error {
+		archived, err := prayerService.ArchiveExpired(ctx)
+		if archived > 0 {
+			log.Printf("Archived %d expired prayer requests", archived)
+		}
+		return err
+	})
+	jobs.Start(ctx)
+	defer jobs.Wait()
+
+	// Initialize server; open streams are closed as soon as shutdown starts
+	// so they don't hold the drain open
+	srv := server.New(cfg, authenticator, limiter, prayerHandler, userHandler, tripHandler, notificationHandler, moderationHandler)
+	srv.RegisterOnShutdown(broker.Close)
 
-	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	return srv.Run(ctx)
+}
+
+// openDatabase connects to MongoDB
+func openDatabase(cfg *config.Config) (*database.DB, error) {
+	db, err := database.New(cfg.Database.URI, cfg.Database.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	log.Printf("Server starting on port %s", port)
-	log.Fatal(srv.Start(port))
+	log.Println("Connected to MongoDB successfully")
+	return db, nil
+}
+
+// closeDatabase disconnects from MongoDB, giving pending operations a few
+// seconds to finish
+func closeDatabase(db *database.DB) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := db.Close(ctx); err != nil {
+		log.Printf("Error closing database: %v", err)
+		return
+	}
+	log.Println("Database connection closed")
 }
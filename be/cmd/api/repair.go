@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"prayerreq-backend/internal/config"
+	prayerRepo "prayerreq-backend/internal/controller/prayer/repository"
+)
+
+// repair implements the repair command, which recomputes denormalized
+// counters from the collections they summarize:
+//
+//	api repair comment-counts   recount the comments on every prayer request
+func repair(args []string) error {
+	if len(args) != 1 || args[0] != "comment-counts" {
+		return fmt.Errorf("usage: %s repair comment-counts", os.Args[0])
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.Storage != config.StorageMongo {
+		return errors.New("repair requires STORAGE_DRIVER=mongo")
+	}
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDatabase(db)
+
+	repaired, err := prayerRepo.NewMongoRepository(db.Database).RepairCommentCounts(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Repaired the comment count of %d prayer requests\n", repaired)
+	return nil
+}
@@ -0,0 +1,173 @@
+package apierror
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Common error codes. Controllers define their own, more specific codes
+// such as "prayer_not_found" alongside these.
+const (
+	CodeInvalidJSON      = "invalid_json"
+	CodeInvalidID        = "invalid_id"
+	CodeInvalidParameter = "invalid_parameter"
+	CodeValidationFailed = "validation_failed"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeFeatureDisabled  = "feature_disabled"
+	CodeRateLimited      = "rate_limited"
+	CodeInternal         = "internal_error"
+)
+
+// FeatureDisabled handles routes whose feature is turned off in the configuration
+func FeatureDisabled(w http.ResponseWriter, r *http.Request) {
+	Write(w, r, NotFound(CodeFeatureDisabled, "This feature is disabled"))
+}
+
+// Error is an API error with a stable, machine-readable code. The cause is
+// logged for server errors but never sent to the client.
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	Details any
+	Err     error
+}
+
+// response is the JSON body written for an Error
+type response struct {
+	Error body `json:"error"`
+}
+
+type body struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Details   any    `json:"details,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// New creates a new API error
+func New(status int, code, message string) *Error {
+	return &Error{
+		Status:  status,
+		Code:    code,
+		Message: message,
+	}
+}
+
+// BadRequest creates a 400 error
+func BadRequest(code, message string) *Error {
+	return New(http.StatusBadRequest, code, message)
+}
+
+// Unauthorized creates a 401 error
+func Unauthorized(code, message string) *Error {
+	return New(http.StatusUnauthorized, code, message)
+}
+
+// Forbidden creates a 403 error
+func Forbidden(code, message string) *Error {
+	return New(http.StatusForbidden, code, message)
+}
+
+// NotFound creates a 404 error
+func NotFound(code, message string) *Error {
+	return New(http.StatusNotFound, code, message)
+}
+
+// Conflict creates a 409 error
+func Conflict(code, message string) *Error {
+	return New(http.StatusConflict, code, message)
+}
+
+// Internal creates a 500 error wrapping the cause
+func Internal(err error) *Error {
+	return New(http.StatusInternalServerError, CodeInternal, "An internal error occurred").WithCause(err)
+}
+
+// InvalidJSON creates the error returned for a request body that can't be decoded
+func InvalidJSON(err error) *Error {
+	return BadRequest(CodeInvalidJSON, "Request body is not valid JSON").WithCause(err)
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Code + ": " + e.Message + ": " + e.Err.Error()
+	}
+	return e.Code + ": " + e.Message
+}
+
+// Unwrap returns the cause of the error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithCause returns a copy of the error wrapping the cause
+func (e *Error) WithCause(err error) *Error {
+	c := *e
+	c.Err = err
+	return &c
+}
+
+// WithDetails returns a copy of the error carrying extra details for the client
+func (e *Error) WithDetails(details any) *Error {
+	c := *e
+	c.Details = details
+	return &c
+}
+
+// IsInvalidID reports whether err came from parsing a malformed ObjectID
+func IsInvalidID(err error) bool {
+	var invalidByte hex.InvalidByteError
+	return errors.Is(err, bson.ErrInvalidHex) || errors.Is(err, hex.ErrLength) || errors.As(err, &invalidByte)
+}
+
+// FromRepository maps an error returned by a repository: a missing document
+// becomes a 404 with the given code, a malformed ID a 400, and anything else
+// an internal error.
+func FromRepository(err error, notFoundCode, notFoundMessage string) *Error {
+	var apiErr *Error
+	switch {
+	case errors.As(err, &apiErr):
+		return apiErr
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return NotFound(notFoundCode, notFoundMessage)
+	case IsInvalidID(err):
+		return BadRequest(CodeInvalidID, "The ID is not a valid identifier").WithCause(err)
+	default:
+		return Internal(err)
+	}
+}
+
+// Write writes err as a JSON error response. Errors that aren't an *Error
+// are treated as internal errors.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		apiErr = Internal(err)
+	}
+
+	requestID := middleware.GetReqID(r.Context())
+	if apiErr.Status >= http.StatusInternalServerError {
+		log.Printf("[%s] %s %s: %v", requestID, r.Method, r.URL.Path, apiErr)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(apiErr.Status)
+	json.NewEncoder(w).Encode(response{Error: body{
+		Code:      apiErr.Code,
+		Message:   apiErr.Message,
+		Details:   apiErr.Details,
+		RequestID: requestID,
+	}})
+}
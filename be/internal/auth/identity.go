@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"net/http"
+	"regexp"
+
+	"prayerreq-backend/internal/apierror"
+)
+
+// DeviceIDHeader carries the anonymous device token of callers that are not
+// logged in. Clients generate it once and keep it in local storage.
+const DeviceIDHeader = "X-Device-ID"
+
+// ErrNoIdentity is returned when a request carries neither a session nor a
+// valid device token
+var ErrNoIdentity = apierror.BadRequest("identity_required",
+	"Request must be authenticated or carry a valid "+DeviceIDHeader+" header")
+
+var deviceIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{8,128}$`)
+
+// Identity returns a stable identifier for the caller: the user ID when
+// logged in, otherwise the anonymous device token. Identities are prefixed
+// with their kind so a device token can never collide with a user ID.
+func Identity(r *http.Request) (string, error) {
+	if user := UserFromContext(r.Context()); user != nil {
+		return "user:" + user.ID.Hex(), nil
+	}
+
+	if deviceID := r.Header.Get(DeviceIDHeader); deviceIDPattern.MatchString(deviceID) {
+		return "device:" + deviceID, nil
+	}
+
+	return "", ErrNoIdentity
+}
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"prayerreq-backend/internal/apierror"
+	"prayerreq-backend/internal/controller/user/data"
+)
+
+// contextKey is the type of the keys this package stores on a context
+type contextKey struct{ name string }
+
+var userContextKey = &contextKey{"user"}
+
+// Error codes for authentication failures
+const (
+	CodeInvalidToken = "invalid_token"
+	CodeTokenExpired = "token_expired"
+)
+
+// TokenError maps a Verify error to an API error
+func TokenError(err error) *apierror.Error {
+	if errors.Is(err, ErrExpiredToken) {
+		return apierror.Unauthorized(CodeTokenExpired, "The token has expired")
+	}
+	return apierror.Unauthorized(CodeInvalidToken, "The token is invalid")
+}
+
+// UserStore is the subset of the user repository the middleware needs
+type UserStore interface {
+	GetUserByID(ctx context.Context, id string) (*data.User, error)
+}
+
+// Authenticator resolves bearer tokens into users
+type Authenticator struct {
+	tokens *TokenManager
+	users  UserStore
+}
+
+// NewAuthenticator creates a new authenticator
+func NewAuthenticator(tokens *TokenManager, users UserStore) *Authenticator {
+	return &Authenticator{
+		tokens: tokens,
+		users:  users,
+	}
+}
+
+// WithUser returns a copy of ctx carrying the authenticated user
+func WithUser(ctx context.Context, user *data.User) context.Context {
+	return context.WithValue(ctx, userContextKey, user)
+}
+
+// UserFromContext returns the authenticated user, or nil for anonymous requests
+func UserFromContext(ctx context.Context) *data.User {
+	user, _ := ctx.Value(userContextKey).(*data.User)
+	return user
+}
+
+// HasRole reports whether the authenticated user has one of the roles
+func HasRole(ctx context.Context, roles ...string) bool {
+	user := UserFromContext(ctx)
+	return user != nil && slices.Contains(roles, user.Role)
+}
+
+// IsAdmin reports whether the authenticated user is an administrator
+func IsAdmin(ctx context.Context) bool {
+	return HasRole(ctx, data.RoleAdmin)
+}
+
+// IsStaff reports whether the authenticated user is a moderator or an administrator
+func IsStaff(ctx context.Context) bool {
+	return HasRole(ctx, data.RoleModerator, data.RoleAdmin)
+}
+
+// Middleware puts the user identified by the bearer token on the request
+// context. Requests without a token pass through anonymously; requests with
+// an invalid or expired token are rejected so clients know to refresh.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			apierror.Write(w, r, apierror.Unauthorized(CodeInvalidToken, "Authorization header must use the Bearer scheme"))
+			return
+		}
+
+		claims, err := a.tokens.Verify(token, AccessToken)
+		if err != nil {
+			apierror.Write(w, r, TokenError(err))
+			return
+		}
+
+		user, err := a.users.GetUserByID(r.Context(), claims.Subject)
+		if err != nil || !user.IsActive {
+			apierror.Write(w, r, apierror.Unauthorized(CodeInvalidToken, "The token belongs to an unknown or inactive user"))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), user)))
+	})
+}
+
+// RequireUser rejects requests that are not authenticated
+func RequireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if UserFromContext(r.Context()) == nil {
+			apierror.Write(w, r, apierror.Unauthorized(apierror.CodeUnauthorized, "Authentication required"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireRole returns a middleware that rejects requests unless the user
+// has one of the roles
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if UserFromContext(r.Context()) == nil {
+				apierror.Write(w, r, apierror.Unauthorized(apierror.CodeUnauthorized, "Authentication required"))
+				return
+			}
+			if !HasRole(r.Context(), roles...) {
+				apierror.Write(w, r, apierror.Forbidden(apierror.CodeForbidden, "Your role doesn't allow this"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireStaff rejects requests that are not made by a moderator or an administrator
+var RequireStaff = RequireRole(data.RoleModerator, data.RoleAdmin)
+
+// RequireAdmin rejects requests that are not made by an administrator
+var RequireAdmin = RequireRole(data.RoleAdmin)
@@ -0,0 +1,376 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// Environments
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+	EnvTest        = "test"
+)
+
+// Storage drivers
+const (
+	StorageMongo  = "mongo"
+	StorageMemory = "memory"
+)
+
+// exampleAuthSecret is the AUTH_SECRET of config.example.env, which is
+// public and so refused in production
+const exampleAuthSecret = "change-me-to-a-long-random-string"
+
+// LogLevels lists the accepted LOG_LEVEL values, most verbose first
+var LogLevels = []string{"debug", "info", "warn", "error"}
+
+// Config holds the application configuration
+type Config struct {
+	Environment string
+	LogLevel    string
+	Storage     string
+	Database    Database
+	Server      Server
+	CORS        CORS
+	Auth        Auth
+	Trash       Trash
+	Archive     Archive
+	RateLimit   RateLimit
+	Moderation  Moderation
+	Features    Features
+}
+
+// Database holds the MongoDB settings
+type Database struct {
+	URI         string
+	Name        string
+	AutoMigrate bool // apply pending migrations at startup
+}
+
+// Server holds the HTTP server settings
+type Server struct {
+	Port              string
+	ReadTimeout       time.Duration // time to read the whole request, including the body
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration // streaming endpoints lift this per request
+	IdleTimeout       time.Duration // keep-alive connections
+	ShutdownTimeout   time.Duration // how long in-flight requests get to finish
+}
+
+// CORS holds the cross-origin settings
+type CORS struct {
+	AllowedOrigins []string
+}
+
+// Auth holds the token settings
+type Auth struct {
+	Secret          string // empty outside production means "generate one at startup"
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+// Trash holds the settings for deleted prayer requests
+type Trash struct {
+	Retention     time.Duration // how long deleted requests can be restored
+	PurgeInterval time.Duration // how often expired requests are removed for good
+}
+
+// Archive holds the settings for expiring prayer requests
+type Archive struct {
+	Interval time.Duration // how often expired requests are archived
+}
+
+// RateLimit holds the request throttling settings
+type RateLimit struct {
+	Enabled        bool
+	TrustedProxies []netip.Prefix // proxies whose X-Forwarded-For header is believed
+	CreatePrayer   Rate           // POST /prayers
+	Pray           Rate           // POST /prayers/{id}/pray and /reactions, sharing one bucket
+	Comment        Rate           // POST /prayers/{id}/comments
+	TripRequest    Rate           // POST /trips/{id}/requests
+	Auth           Rate           // login, registration and token refresh, per IP
+}
+
+// Rate is a number of requests allowed per period. Requests are allowed in
+// bursts of up to Limit, refilling evenly over Period.
+type Rate struct {
+	Limit  int
+	Period time.Duration
+}
+
+// Moderation holds the content screening settings
+type Moderation struct {
+	Filter          bool     // screen new prayer requests and comments
+	Wordlist        []string // words and phrases that hold content for review
+	WordlistFile    string   // optional file with more entries, one per line
+	MaxLinks        int      // links allowed in one post before it is held
+	ReportThreshold int      // reports that hold published content for review; 0 never does
+}
+
+// Features holds toggles for optional functionality
+type Features struct {
+	Registration bool // POST /auth/register
+	LiveStream   bool // GET /prayers/stream
+}
+
+// IsProduction reports whether the app runs in production
+func (c *Config) IsProduction() bool {
+	return c.Environment == EnvProduction
+}
+
+// SlogLevel returns the log level for the slog default logger
+func (c *Config) SlogLevel() slog.Level {
+	switch c.LogLevel {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Load reads the configuration from the environment. Variables from the
+// file named by ENV_FILE (default .env) are loaded first if it exists; they
+// never override variables that are already set.
+func Load() (*Config, error) {
+	envFile, explicit := os.LookupEnv("ENV_FILE")
+	if !explicit {
+		envFile = ".env"
+	}
+	if err := godotenv.Load(envFile); err != nil && (explicit || !errors.Is(err, os.ErrNotExist)) {
+		return nil, fmt.Errorf("config: loading %s: %w", envFile, err)
+	}
+
+	l := &loader{}
+	cfg := &Config{
+		Environment: l.string("ENVIRONMENT", EnvDevelopment),
+		LogLevel:    strings.ToLower(l.string("LOG_LEVEL", "info")),
+		Storage:     l.string("STORAGE_DRIVER", StorageMongo),
+		Database: Database{
+			URI:         l.string("MONGODB_URI", "mongodb://localhost:27017"),
+			Name:        l.string("DB_NAME", "prayerreq"),
+			AutoMigrate: l.bool("DB_AUTO_MIGRATE", true),
+		},
+		Server: Server{
+			Port:              l.string("PORT", "8080"),
+			ReadTimeout:       l.duration("SERVER_READ_TIMEOUT", 15*time.Second),
+			ReadHeaderTimeout: l.duration("SERVER_READ_HEADER_TIMEOUT", 5*time.Second),
+			WriteTimeout:      l.duration("SERVER_WRITE_TIMEOUT", 30*time.Second),
+			IdleTimeout:       l.duration("SERVER_IDLE_TIMEOUT", 120*time.Second),
+			ShutdownTimeout:   l.duration("SERVER_SHUTDOWN_TIMEOUT", 20*time.Second),
+		},
+		CORS: CORS{
+			AllowedOrigins: l.list("ALLOWED_ORIGINS", []string{"*"}),
+		},
+		Auth: Auth{
+			Secret:          l.string("AUTH_SECRET", ""),
+			AccessTokenTTL:  l.duration("ACCESS_TOKEN_TTL", 15*time.Minute),
+			RefreshTokenTTL: l.duration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		},
+		Trash: Trash{
+			Retention:     l.duration("TRASH_RETENTION", 30*24*time.Hour),
+			PurgeInterval: l.duration("TRASH_PURGE_INTERVAL", time.Hour),
+		},
+		Archive: Archive{
+			Interval: l.duration("ARCHIVE_INTERVAL", 5*time.Minute),
+		},
+		RateLimit: RateLimit{
+			Enabled:        l.bool("RATE_LIMIT_ENABLED", true),
+			TrustedProxies: l.prefixes("RATE_LIMIT_TRUSTED_PROXIES"),
+			CreatePrayer:   l.rate("RATE_LIMIT_CREATE_PRAYER", Rate{Limit: 5, Period: 10 * time.Minute}),
+			Pray:           l.rate("RATE_LIMIT_PRAY", Rate{Limit: 30, Period: time.Minute}),
+			Comment:        l.rate("RATE_LIMIT_COMMENT", Rate{Limit: 10, Period: time.Minute}),
+			TripRequest:    l.rate("RATE_LIMIT_TRIP_REQUEST", Rate{Limit: 10, Period: 10 * time.Minute}),
+			Auth:           l.rate("RATE_LIMIT_AUTH", Rate{Limit: 10, Period: 15 * time.Minute}),
+		},
+		Moderation: Moderation{
+			Filter:          l.bool("MODERATION_FILTER", true),
+			Wordlist:        l.list("MODERATION_WORDLIST", nil),
+			WordlistFile:    l.string("MODERATION_WORDLIST_FILE", ""),
+			MaxLinks:        l.int("MODERATION_MAX_LINKS", 2),
+			ReportThreshold: l.int("MODERATION_REPORT_THRESHOLD", 3),
+		},
+		Features: Features{
+			Registration: l.bool("FEATURE_REGISTRATION", true),
+			LiveStream:   l.bool("FEATURE_LIVE_STREAM", true),
+		},
+	}
+
+	if err := errors.Join(append(l.errs, cfg.Validate())...); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// Validate checks that the values are usable together
+func (c *Config) Validate() error {
+	var errs []error
+
+	if !slices.Contains([]string{EnvDevelopment, EnvProduction, EnvTest}, c.Environment) {
+		errs = append(errs, fmt.Errorf("ENVIRONMENT must be %s, %s or %s, got %q", EnvDevelopment, EnvProduction, EnvTest, c.Environment))
+	}
+	if !slices.Contains(LogLevels, c.LogLevel) {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL must be one of %s, got %q", strings.Join(LogLevels, ", "), c.LogLevel))
+	}
+	if c.Storage != StorageMongo && c.Storage != StorageMemory {
+		errs = append(errs, fmt.Errorf("STORAGE_DRIVER must be %s or %s, got %q", StorageMongo, StorageMemory, c.Storage))
+	}
+	if c.Storage == StorageMongo && (c.Database.URI == "" || c.Database.Name == "") {
+		errs = append(errs, errors.New("MONGODB_URI and DB_NAME are required with the mongo storage driver"))
+	}
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fmt.Errorf("PORT must be a number between 1 and 65535, got %q", c.Server.Port))
+	}
+	for name, d := range map[string]time.Duration{
+		"SERVER_READ_TIMEOUT":        c.Server.ReadTimeout,
+		"SERVER_READ_HEADER_TIMEOUT": c.Server.ReadHeaderTimeout,
+		"SERVER_WRITE_TIMEOUT":       c.Server.WriteTimeout,
+		"SERVER_IDLE_TIMEOUT":        c.Server.IdleTimeout,
+		"SERVER_SHUTDOWN_TIMEOUT":    c.Server.ShutdownTimeout,
+		"ACCESS_TOKEN_TTL":           c.Auth.AccessTokenTTL,
+		"REFRESH_TOKEN_TTL":          c.Auth.RefreshTokenTTL,
+		"TRASH_RETENTION":            c.Trash.Retention,
+		"TRASH_PURGE_INTERVAL":       c.Trash.PurgeInterval,
+		"ARCHIVE_INTERVAL":           c.Archive.Interval,
+	} {
+		if d <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %s", name, d))
+		}
+	}
+	if len(c.CORS.AllowedOrigins) == 0 {
+		errs = append(errs, errors.New("ALLOWED_ORIGINS must list at least one origin"))
+	}
+
+	if c.IsProduction() {
+		if len(c.Auth.Secret) < 32 {
+			errs = append(errs, errors.New("AUTH_SECRET must be at least 32 characters in production"))
+		} else if c.Auth.Secret == exampleAuthSecret {
+			errs = append(errs, errors.New("AUTH_SECRET is still the placeholder from config.example.env"))
+		}
+		if c.Storage == StorageMemory {
+			errs = append(errs, errors.New("STORAGE_DRIVER=memory is not allowed in production"))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// loader reads typed environment variables, collecting parse errors
+type loader struct {
+	errs []error
+}
+
+func (l *loader) string(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && strings.TrimSpace(value) != "" {
+		return strings.TrimSpace(value)
+	}
+	return fallback
+}
+
+func (l *loader) duration(key string, fallback time.Duration) time.Duration {
+	value := l.string(key, "")
+	if value == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s must be a duration such as 30s or 5m, got %q", key, value))
+		return fallback
+	}
+	return d
+}
+
+func (l *loader) bool(key string, fallback bool) bool {
+	value := l.string(key, "")
+	if value == "" {
+		return fallback
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s must be true or false, got %q", key, value))
+		return fallback
+	}
+	return b
+}
+
+// int reads a non-negative integer
+func (l *loader) int(key string, fallback int) int {
+	value := l.string(key, "")
+	if value == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		l.errs = append(l.errs, fmt.Errorf("%s must be a whole number of zero or more, got %q", key, value))
+		return fallback
+	}
+	return n
+}
+
+// list reads a comma separated list, ignoring blank entries
+func (l *loader) list(key string, fallback []string) []string {
+	value := l.string(key, "")
+	if value == "" {
+		return fallback
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// rate reads a rate written as <limit>/<period>, such as 10/1m
+func (l *loader) rate(key string, fallback Rate) Rate {
+	value := l.string(key, "")
+	if value == "" {
+		return fallback
+	}
+
+	limit, period, _ := strings.Cut(value, "/")
+	n, err := strconv.Atoi(strings.TrimSpace(limit))
+	d, derr := time.ParseDuration(strings.TrimSpace(period))
+	if err != nil || derr != nil || n < 1 || d <= 0 {
+		l.errs = append(l.errs, fmt.Errorf("%s must be a positive count and duration such as 10/1m, got %q", key, value))
+		return fallback
+	}
+	return Rate{Limit: n, Period: d}
+}
+
+// prefixes reads a comma separated list of IP addresses and CIDR ranges
+func (l *loader) prefixes(key string) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for _, item := range l.list(key, nil) {
+		prefix, err := netip.ParsePrefix(item)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(item)
+			if addrErr != nil {
+				l.errs = append(l.errs, fmt.Errorf("%s must list IP addresses or CIDR ranges, got %q", key, item))
+				continue
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadRefusesExampleSecretInProduction(t *testing.T) {
+	t.Setenv("ENV_FILE", "../../config.example.env")
+	t.Setenv("ENVIRONMENT", EnvProduction)
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "AUTH_SECRET is still the placeholder") {
+		t.Fatalf("Load() error = %v, want the example AUTH_SECRET refused", err)
+	}
+
+	t.Setenv("AUTH_SECRET", strings.Repeat("k", 32))
+	if _, err := Load(); err != nil {
+		t.Errorf("Load() with a real secret: %v", err)
+	}
+}
@@ -0,0 +1,12 @@
+package data
+
+import "go.mongodb.org/mongo-driver/v2/bson"
+
+// ReviewResult represents the outcome of a moderator's decision on a
+// prayer request or comment
+type ReviewResult struct {
+	TargetType       string         `json:"target_type"` // "prayer_request" or "comment"
+	TargetID         bson.ObjectID  `json:"target_id"`
+	ModerationStatus string         `json:"moderation_status"`
+	BannedUserID     *bson.ObjectID `json:"banned_user_id,omitempty"` // set when the author's account was deactivated
+}
@@ -0,0 +1,22 @@
+package moderation
+
+import "prayerreq-backend/internal/apierror"
+
+// Error codes returned by the moderation endpoints
+const (
+	CodePrayerNotFound  = "prayer_not_found"
+	CodeCommentNotFound = "comment_not_found"
+	CodeNoAuthorAccount = "no_author_account"
+	CodeCannotBanSelf   = "cannot_ban_self"
+	CodeCannotBanStaff  = "cannot_ban_staff"
+)
+
+// prayerNotFound maps an error from looking up a prayer request by ID
+func prayerNotFound(err error) *apierror.Error {
+	return apierror.FromRepository(err, CodePrayerNotFound, "Prayer request not found")
+}
+
+// commentNotFound maps an error from looking up a comment by ID
+func commentNotFound(err error) *apierror.Error {
+	return apierror.FromRepository(err, CodeCommentNotFound, "Comment not found")
+}
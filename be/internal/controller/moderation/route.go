@@ -0,0 +1,43 @@
+package moderation
+
+import (
+	"prayerreq-backend/internal/auth"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// NewHTTPHandler creates a new HTTP handler for moderation
+func NewHTTPHandler(service *Service) *HTTPHandler {
+	return &HTTPHandler{
+		service: service,
+	}
+}
+
+// HTTPHandler handles HTTP requests for moderation
+type HTTPHandler struct {
+	service *Service
+}
+
+// RegisterRoutes registers the moderation queue routes, which are limited
+// to moderators and administrators
+func (h *HTTPHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/moderation", func(r chi.Router) {
+		r.Use(auth.RequireStaff)
+
+		r.Route("/prayers", func(r chi.Router) {
+			r.Get("/", h.service.GetPrayerQueue)
+			r.Get("/{id}/reports", h.service.GetPrayerReports)
+			r.Post("/{id}/approve", h.service.ApprovePrayer)
+			r.Post("/{id}/reject", h.service.RejectPrayer)
+			r.Post("/{id}/ban", h.service.BanPrayerAuthor)
+		})
+
+		r.Route("/comments", func(r chi.Router) {
+			r.Get("/", h.service.GetCommentQueue)
+			r.Get("/{id}/reports", h.service.GetCommentReports)
+			r.Post("/{id}/approve", h.service.ApproveComment)
+			r.Post("/{id}/reject", h.service.RejectComment)
+			r.Post("/{id}/ban", h.service.BanCommentAuthor)
+		})
+	})
+}
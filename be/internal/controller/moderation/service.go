@@ -0,0 +1,312 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"prayerreq-backend/internal/apierror"
+	"prayerreq-backend/internal/auth"
+	"prayerreq-backend/internal/controller/moderation/data"
+	prayerData "prayerreq-backend/internal/controller/prayer/data"
+	userData "prayerreq-backend/internal/controller/user/data"
+	"prayerreq-backend/internal/moderation"
+	"prayerreq-backend/internal/pagination"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Content is the subset of the prayer service that moderators review
+type Content interface {
+	GetPrayerRequest(ctx context.Context, id string) (*prayerData.PrayerRequest, error)
+	GetComment(ctx context.Context, id string) (*prayerData.Comment, error)
+	GetPrayerRequestsByStatus(ctx context.Context, status string, page pagination.Params) (*pagination.Page[*prayerData.PrayerRequest], error)
+	GetCommentsByStatus(ctx context.Context, status string, page pagination.Params) (*pagination.Page[*prayerData.Comment], error)
+	GetReports(ctx context.Context, target, id string, page pagination.Params) (*pagination.Page[*prayerData.Report], error)
+	ModeratePrayerRequest(ctx context.Context, id, status string) (*prayerData.PrayerRequest, error)
+	ModerateComment(ctx context.Context, id, status string) (*prayerData.Comment, error)
+}
+
+// Accounts is the subset of the user repository needed to ban authors
+type Accounts interface {
+	GetUserByID(ctx context.Context, id string) (*userData.User, error)
+	UpdateUser(ctx context.Context, id string, user *userData.User) error
+}
+
+// Service handles the moderation queue
+type Service struct {
+	content  Content
+	accounts Accounts
+}
+
+// NewService creates a new moderation service
+func NewService(content Content, accounts Accounts) *Service {
+	return &Service{
+		content:  content,
+		accounts: accounts,
+	}
+}
+
+// GetPrayerQueue handles GET /api/v1/moderation/prayers?status=&limit=&cursor=
+// Prayer requests held for review are listed oldest first; pass
+// status=rejected to see the rejected ones instead.
+func (s *Service) GetPrayerQueue(w http.ResponseWriter, r *http.Request) {
+	status, page, err := queueParams(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	requests, err := s.content.GetPrayerRequestsByStatus(r.Context(), status, page)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(requests)
+}
+
+// GetCommentQueue handles GET /api/v1/moderation/comments?status=&limit=&cursor=
+func (s *Service) GetCommentQueue(w http.ResponseWriter, r *http.Request) {
+	status, page, err := queueParams(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	comments, err := s.content.GetCommentsByStatus(r.Context(), status, page)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comments)
+}
+
+// GetPrayerReports handles GET /api/v1/moderation/prayers/{id}/reports?limit=&cursor=
+func (s *Service) GetPrayerReports(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if _, err := s.content.GetPrayerRequest(r.Context(), id); err != nil {
+		apierror.Write(w, r, prayerNotFound(err))
+		return
+	}
+
+	s.writeReports(w, r, prayerData.ReportTargetPrayer, id)
+}
+
+// GetCommentReports handles GET /api/v1/moderation/comments/{id}/reports?limit=&cursor=
+func (s *Service) GetCommentReports(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if _, err := s.content.GetComment(r.Context(), id); err != nil {
+		apierror.Write(w, r, commentNotFound(err))
+		return
+	}
+
+	s.writeReports(w, r, prayerData.ReportTargetComment, id)
+}
+
+// ApprovePrayer handles POST /api/v1/moderation/prayers/{id}/approve
+func (s *Service) ApprovePrayer(w http.ResponseWriter, r *http.Request) {
+	s.reviewPrayer(w, r, moderation.StatusPublished)
+}
+
+// RejectPrayer handles POST /api/v1/moderation/prayers/{id}/reject
+func (s *Service) RejectPrayer(w http.ResponseWriter, r *http.Request) {
+	s.reviewPrayer(w, r, moderation.StatusRejected)
+}
+
+// BanPrayerAuthor handles POST /api/v1/moderation/prayers/{id}/ban
+// The prayer request is rejected and its author's account deactivated.
+func (s *Service) BanPrayerAuthor(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	request, err := s.content.GetPrayerRequest(r.Context(), id)
+	if err != nil {
+		apierror.Write(w, r, prayerNotFound(err))
+		return
+	}
+
+	author, err := s.bannableAuthor(r, request.UserID)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	if _, err := s.content.ModeratePrayerRequest(r.Context(), id, moderation.StatusRejected); err != nil {
+		apierror.Write(w, r, prayerNotFound(err))
+		return
+	}
+	if err := s.ban(r.Context(), author); err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	writeResult(w, data.ReviewResult{
+		TargetType:       prayerData.ReportTargetPrayer,
+		TargetID:         request.ID,
+		ModerationStatus: moderation.StatusRejected,
+		BannedUserID:     &author.ID,
+	})
+}
+
+// ApproveComment handles POST /api/v1/moderation/comments/{id}/approve
+func (s *Service) ApproveComment(w http.ResponseWriter, r *http.Request) {
+	s.reviewComment(w, r, moderation.StatusPublished)
+}
+
+// RejectComment handles POST /api/v1/moderation/comments/{id}/reject
+func (s *Service) RejectComment(w http.ResponseWriter, r *http.Request) {
+	s.reviewComment(w, r, moderation.StatusRejected)
+}
+
+// BanCommentAuthor handles POST /api/v1/moderation/comments/{id}/ban
+// The comment is rejected and its author's account deactivated.
+func (s *Service) BanCommentAuthor(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	comment, err := s.content.GetComment(r.Context(), id)
+	if err != nil {
+		apierror.Write(w, r, commentNotFound(err))
+		return
+	}
+
+	author, err := s.bannableAuthor(r, comment.UserID)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	if _, err := s.content.ModerateComment(r.Context(), id, moderation.StatusRejected); err != nil {
+		apierror.Write(w, r, commentNotFound(err))
+		return
+	}
+	if err := s.ban(r.Context(), author); err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	writeResult(w, data.ReviewResult{
+		TargetType:       prayerData.ReportTargetComment,
+		TargetID:         comment.ID,
+		ModerationStatus: moderation.StatusRejected,
+		BannedUserID:     &author.ID,
+	})
+}
+
+// reviewPrayer moves a prayer request to the moderator's chosen status
+func (s *Service) reviewPrayer(w http.ResponseWriter, r *http.Request, status string) {
+	request, err := s.content.ModeratePrayerRequest(r.Context(), chi.URLParam(r, "id"), status)
+	if err != nil {
+		apierror.Write(w, r, prayerNotFound(err))
+		return
+	}
+
+	writeResult(w, data.ReviewResult{
+		TargetType:       prayerData.ReportTargetPrayer,
+		TargetID:         request.ID,
+		ModerationStatus: request.ModerationStatus,
+	})
+}
+
+// reviewComment moves a comment to the moderator's chosen status
+func (s *Service) reviewComment(w http.ResponseWriter, r *http.Request, status string) {
+	comment, err := s.content.ModerateComment(r.Context(), chi.URLParam(r, "id"), status)
+	if err != nil {
+		apierror.Write(w, r, commentNotFound(err))
+		return
+	}
+
+	writeResult(w, data.ReviewResult{
+		TargetType:       prayerData.ReportTargetComment,
+		TargetID:         comment.ID,
+		ModerationStatus: comment.ModerationStatus,
+	})
+}
+
+// bannableAuthor returns the account that wrote a piece of content. Content
+// posted without an account has no one to ban, and moderators can't ban
+// themselves.
+func (s *Service) bannableAuthor(r *http.Request, userID bson.ObjectID) (*userData.User, error) {
+	if userID.IsZero() {
+		return nil, apierror.Conflict(CodeNoAuthorAccount, "The author posted without an account and can't be banned")
+	}
+	if user := auth.UserFromContext(r.Context()); user != nil && user.ID == userID {
+		return nil, apierror.Conflict(CodeCannotBanSelf, "You can't ban yourself")
+	}
+
+	author, err := s.accounts.GetUserByID(r.Context(), userID.Hex())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, apierror.Conflict(CodeNoAuthorAccount, "The author's account no longer exists")
+	}
+	if err != nil {
+		return nil, apierror.Internal(err)
+	}
+	if author.IsStaff() {
+		return nil, apierror.Conflict(CodeCannotBanStaff, "Moderators and administrators can't be banned")
+	}
+
+	return author, nil
+}
+
+// ban deactivates an account, which signs it out everywhere
+func (s *Service) ban(ctx context.Context, user *userData.User) error {
+	if !user.IsActive {
+		return nil
+	}
+
+	user.IsActive = false
+	user.UpdatedAt = time.Now()
+	return s.accounts.UpdateUser(ctx, user.ID.Hex(), user)
+}
+
+// writeReports writes a page of the reports on a prayer request or comment
+func (s *Service) writeReports(w http.ResponseWriter, r *http.Request, target, id string) {
+	page, err := pagination.FromRequest(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	reports, err := s.content.GetReports(r.Context(), target, id, page)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
+
+// queueParams parses the status and pagination of a queue listing
+func queueParams(r *http.Request) (string, pagination.Params, error) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = moderation.StatusPendingReview
+	case moderation.StatusPendingReview, moderation.StatusRejected:
+	default:
+		return "", pagination.Params{}, apierror.BadRequest(apierror.CodeInvalidParameter,
+			"status must be pending_review or rejected")
+	}
+
+	page, err := pagination.FromRequest(r)
+	if err != nil {
+		return "", pagination.Params{}, err
+	}
+
+	return status, page, nil
+}
+
+// writeResult writes the outcome of a review
+func writeResult(w http.ResponseWriter, result data.ReviewResult) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
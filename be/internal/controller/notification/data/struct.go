@@ -0,0 +1,29 @@
+package data
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Notification types
+const (
+	TypePrayerAnswered = "prayer_answered"
+)
+
+// Notification tells someone about a prayer request they prayed for. It is
+// addressed to an identity, so anonymous devices receive them too.
+type Notification struct {
+	ID              bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	Identity        string        `json:"-" bson:"identity"` // "user:<id>" or "device:<token>"
+	Type            string        `json:"type" bson:"type"`  // "prayer_answered"
+	PrayerRequestID bson.ObjectID `json:"prayer_request_id" bson:"prayer_request_id"`
+	Message         string        `json:"message" bson:"message"`
+	ReadAt          *time.Time    `json:"read_at,omitempty" bson:"read_at,omitempty"`
+	CreatedAt       time.Time     `json:"created_at" bson:"created_at"`
+}
+
+// ReadResult represents the outcome of marking notifications as read
+type ReadResult struct {
+	Marked int `json:"marked"`
+}
@@ -0,0 +1,13 @@
+package notification
+
+import "prayerreq-backend/internal/apierror"
+
+// Error codes returned by the notification endpoints
+const (
+	CodeNotificationNotFound = "notification_not_found"
+)
+
+// notificationNotFound maps an error from looking up a notification by ID
+func notificationNotFound(err error) *apierror.Error {
+	return apierror.FromRepository(err, CodeNotificationNotFound, "Notification not found")
+}
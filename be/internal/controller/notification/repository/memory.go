@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"prayerreq-backend/internal/controller/notification/data"
+	"prayerreq-backend/internal/pagination"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// memoryRepository implements Repository interface in memory.
+// It mirrors the behaviour of mongoRepository: results are ordered the way
+// the Mongo queries sort them, missing documents yield mongo.ErrNoDocuments
+// and malformed IDs fail the same way ObjectIDFromHex does.
+type memoryRepository struct {
+	mu            sync.RWMutex
+	notifications []*data.Notification
+}
+
+// NewMemoryRepository creates a new in-memory repository for notifications
+func NewMemoryRepository() Repository {
+	return &memoryRepository{}
+}
+
+// CreateNotifications stores a batch of notifications
+func (r *memoryRepository) CreateNotifications(ctx context.Context, notifications []*data.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, n := range notifications {
+		stored := copyNotification(n)
+		if stored.ID.IsZero() {
+			stored.ID = bson.NewObjectID()
+		}
+		r.notifications = append(r.notifications, stored)
+	}
+	return nil
+}
+
+// GetNotifications retrieves a page of an identity's notifications,
+// optionally only the unread ones
+func (r *memoryRepository) GetNotifications(ctx context.Context, identity string, unreadOnly bool, page pagination.Params) (*pagination.Page[*data.Notification], error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var notifications []*data.Notification
+	for _, n := range r.notifications {
+		if n.Identity == identity && (!unreadOnly || n.ReadAt == nil) {
+			notifications = append(notifications, copyNotification(n))
+		}
+	}
+
+	return pagination.Slice(notifications, page, notificationsDesc, notificationCursor), nil
+}
+
+// MarkRead marks one of an identity's notifications as read. Marking a
+// read notification again keeps the first read time.
+func (r *memoryRepository) MarkRead(ctx context.Context, identity, id string, readAt time.Time) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.notifications, func(n *data.Notification) bool {
+		return n.ID == objectID && n.Identity == identity
+	})
+	if i < 0 {
+		return mongo.ErrNoDocuments
+	}
+	if r.notifications[i].ReadAt == nil {
+		r.notifications[i].ReadAt = &readAt
+	}
+	return nil
+}
+
+// MarkAllRead marks every unread notification of an identity as read and
+// reports how many
+func (r *memoryRepository) MarkAllRead(ctx context.Context, identity string, readAt time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	marked := 0
+	for _, n := range r.notifications {
+		if n.Identity == identity && n.ReadAt == nil {
+			n.ReadAt = &readAt
+			marked++
+		}
+	}
+
+	return marked, nil
+}
+
+// DeletePrayerNotifications deletes every notification about the prayer
+// requests
+func (r *memoryRepository) DeletePrayerNotifications(ctx context.Context, prayerRequestIDs []bson.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.notifications = slices.DeleteFunc(r.notifications, func(n *data.Notification) bool {
+		return slices.Contains(prayerRequestIDs, n.PrayerRequestID)
+	})
+	return nil
+}
+
+// copyNotification returns a deep copy so callers can't mutate stored state
+func copyNotification(n *data.Notification) *data.Notification {
+	c := *n
+	if n.ReadAt != nil {
+		readAt := *n.ReadAt
+		c.ReadAt = &readAt
+	}
+	return &c
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"prayerreq-backend/internal/controller/notification/data"
+	"prayerreq-backend/internal/pagination"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Repository defines the interface for notification data access
+type Repository interface {
+	CreateNotifications(ctx context.Context, notifications []*data.Notification) error
+	GetNotifications(ctx context.Context, identity string, unreadOnly bool, page pagination.Params) (*pagination.Page[*data.Notification], error)
+	MarkRead(ctx context.Context, identity, id string, readAt time.Time) error
+	MarkAllRead(ctx context.Context, identity string, readAt time.Time) (int, error)
+	DeletePrayerNotifications(ctx context.Context, prayerRequestIDs []bson.ObjectID) error
+}
+
+// Notifications are listed newest first
+const notificationsDesc = true
+
+// insertBatchSize is the number of notifications inserted per round trip
+const insertBatchSize = 500
+
+// notificationCursor returns the pagination cursor for a notification
+func notificationCursor(n *data.Notification) pagination.Cursor {
+	return pagination.Cursor{Time: n.CreatedAt, ID: n.ID}
+}
+
+// mongoRepository implements Repository interface using MongoDB
+type mongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRepository creates a new MongoDB repository for notifications
+func NewMongoRepository(db *mongo.Database) Repository {
+	return &mongoRepository{
+		collection: db.Collection("notifications"),
+	}
+}
+
+// CreateNotifications stores a batch of notifications
+func (r *mongoRepository) CreateNotifications(ctx context.Context, notifications []*data.Notification) error {
+	for start := 0; start < len(notifications); start += insertBatchSize {
+		batch := notifications[start:min(start+insertBatchSize, len(notifications))]
+		if _, err := r.collection.InsertMany(ctx, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetNotifications retrieves a page of an identity's notifications,
+// optionally only the unread ones
+func (r *mongoRepository) GetNotifications(ctx context.Context, identity string, unreadOnly bool, page pagination.Params) (*pagination.Page[*data.Notification], error) {
+	filter := bson.M{"identity": identity}
+	if unreadOnly {
+		filter["read_at"] = nil
+	}
+
+	return pagination.FindPage(ctx, r.collection, filter, page, notificationsDesc, notificationCursor)
+}
+
+// MarkRead marks one of an identity's notifications as read. Marking a
+// read notification again keeps the first read time.
+func (r *mongoRepository) MarkRead(ctx context.Context, identity, id string, readAt time.Time) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": objectID, "identity": identity},
+		bson.A{bson.M{"$set": bson.M{"read_at": bson.M{"$ifNull": bson.A{"$read_at", readAt}}}}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// MarkAllRead marks every unread notification of an identity as read and
+// reports how many
+func (r *mongoRepository) MarkAllRead(ctx context.Context, identity string, readAt time.Time) (int, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"identity": identity, "read_at": nil},
+		bson.M{"$set": bson.M{"read_at": readAt}},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(result.ModifiedCount), nil
+}
+
+// DeletePrayerNotifications deletes every notification about the prayer
+// requests
+func (r *mongoRepository) DeletePrayerNotifications(ctx context.Context, prayerRequestIDs []bson.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"prayer_request_id": bson.M{"$in": prayerRequestIDs}})
+	return err
+}
@@ -0,0 +1,26 @@
+package notification
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// NewHTTPHandler creates a new HTTP handler for notifications
+func NewHTTPHandler(service *Service) *HTTPHandler {
+	return &HTTPHandler{
+		service: service,
+	}
+}
+
+// HTTPHandler handles HTTP requests for notifications
+type HTTPHandler struct {
+	service *Service
+}
+
+// RegisterRoutes registers notification routes
+func (h *HTTPHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/notifications", func(r chi.Router) {
+		r.Get("/", h.service.GetNotifications)
+		r.Post("/read", h.service.MarkAllRead)
+		r.Post("/{id}/read", h.service.MarkRead)
+	})
+}
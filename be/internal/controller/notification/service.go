@@ -0,0 +1,128 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"prayerreq-backend/internal/apierror"
+	"prayerreq-backend/internal/auth"
+	"prayerreq-backend/internal/controller/notification/data"
+	"prayerreq-backend/internal/controller/notification/repository"
+	"prayerreq-backend/internal/pagination"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Service handles notification business logic
+type Service struct {
+	repo repository.Repository
+}
+
+// NewService creates a new notification service
+func NewService(repo repository.Repository) *Service {
+	return &Service{
+		repo: repo,
+	}
+}
+
+// Notify sends the same notification about a prayer request to every identity
+func (s *Service) Notify(ctx context.Context, identities []string, notificationType string, prayerRequestID bson.ObjectID, message string) error {
+	if len(identities) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	notifications := make([]*data.Notification, len(identities))
+	for i, identity := range identities {
+		notifications[i] = &data.Notification{
+			ID:              bson.NewObjectID(),
+			Identity:        identity,
+			Type:            notificationType,
+			PrayerRequestID: prayerRequestID,
+			Message:         message,
+			CreatedAt:       now,
+		}
+	}
+
+	return s.repo.CreateNotifications(ctx, notifications)
+}
+
+// DeletePrayerNotifications deletes the notifications about prayer requests
+// that are being purged, so none is left pointing at a missing request
+func (s *Service) DeletePrayerNotifications(ctx context.Context, prayerRequestIDs []bson.ObjectID) error {
+	return s.repo.DeletePrayerNotifications(ctx, prayerRequestIDs)
+}
+
+// GetNotifications handles GET /api/v1/notifications?unread=&limit=&cursor=
+// Callers see the notifications addressed to their identity, newest first.
+func (s *Service) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	identity, err := auth.Identity(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	var unreadOnly bool
+	if value := r.URL.Query().Get("unread"); value != "" {
+		unreadOnly, err = strconv.ParseBool(value)
+		if err != nil {
+			apierror.Write(w, r, apierror.BadRequest(apierror.CodeInvalidParameter, "unread must be true or false"))
+			return
+		}
+	}
+
+	page, err := pagination.FromRequest(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	notifications, err := s.repo.GetNotifications(r.Context(), identity, unreadOnly, page)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(notifications)
+}
+
+// MarkRead handles POST /api/v1/notifications/{id}/read
+func (s *Service) MarkRead(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	identity, err := auth.Identity(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	if err := s.repo.MarkRead(r.Context(), identity, id, time.Now()); err != nil {
+		apierror.Write(w, r, notificationNotFound(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MarkAllRead handles POST /api/v1/notifications/read
+func (s *Service) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	identity, err := auth.Identity(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	marked, err := s.repo.MarkAllRead(r.Context(), identity, time.Now())
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data.ReadResult{Marked: marked})
+}
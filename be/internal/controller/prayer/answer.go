@@ -0,0 +1,133 @@
+package prayer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"prayerreq-backend/internal/apierror"
+	notificationData "prayerreq-backend/internal/controller/notification/data"
+	"prayerreq-backend/internal/controller/prayer/data"
+	"prayerreq-backend/internal/pagination"
+	"prayerreq-backend/internal/validate"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// AnswerPrayer handles POST /api/v1/prayers/{id}/answer
+// The owner marks their request answered, optionally sharing a testimony.
+// Everyone who prayed for it is notified the first time; answering again
+// only replaces the testimony, and leaves it as it is when none is sent.
+func (s *Service) AnswerPrayer(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	prayer, err := s.repo.GetPrayerRequestByID(r.Context(), id)
+	if err != nil {
+		apierror.Write(w, r, prayerNotFound(err))
+		return
+	}
+
+	if !isOwner(r, prayer) {
+		apierror.Write(w, r, apierror.Forbidden(CodeNotOwner, "Only the owner can mark this prayer request answered"))
+		return
+	}
+
+	// The body is optional since the testimony is
+	var input data.AnswerPrayerInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		apierror.Write(w, r, apierror.InvalidJSON(err))
+		return
+	}
+	if err := validate.Struct(input); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	firstAnswer := prayer.AnsweredAt == nil
+	now := time.Now()
+	update := &data.PrayerRequestUpdate{Testimony: input.Testimony, UpdatedAt: now}
+	if firstAnswer {
+		update.AnsweredAt = &now
+	}
+
+	// The testimony is screened like an edit; a flagged one holds the
+	// request for review
+	if input.Testimony != nil {
+		if screened := s.screen(r.Context(), *input.Testimony); !isPublished(screened) {
+			update.ModerationStatus = &screened.ModerationStatus
+			update.ModerationReasons = screened.ModerationReasons
+		}
+	}
+
+	// A request that isn't published announces its answer once approved
+	published := isPublished(prayer.Moderation) && update.ModerationStatus == nil
+	announce := published && (firstAnswer || prayer.AnswerPending)
+	if pending := !published && (firstAnswer || prayer.AnswerPending); pending != prayer.AnswerPending {
+		update.AnswerPending = &pending
+	}
+
+	prayer, err = s.repo.UpdatePrayerRequest(r.Context(), id, update)
+	if err != nil {
+		apierror.Write(w, r, prayerNotFound(err))
+		return
+	}
+
+	if announce {
+		s.announceAnswer(r.Context(), prayer)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(shown(r.Context(), prayer))
+}
+
+// GetAnsweredPrayers handles GET /api/v1/prayers/answered?category=&limit=&cursor=
+// It is the public feed of testimonies, most recently answered first.
+func (s *Service) GetAnsweredPrayers(w http.ResponseWriter, r *http.Request) {
+	page, err := pagination.FromRequest(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	prayers, err := s.repo.GetAnsweredPrayerRequests(r.Context(), r.URL.Query().Get("category"), page)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(shownPage(r.Context(), prayers))
+}
+
+// announceAnswer records that a prayer request was answered and notifies
+// everyone who prayed for it
+func (s *Service) announceAnswer(ctx context.Context, request *data.PrayerRequest) {
+	s.recordEvent(ctx, data.ActivityPrayerAnswered, request,
+		fmt.Sprintf("Prayer answered: %q", request.Title), request)
+	s.notifyPrayers(ctx, request,
+		fmt.Sprintf("A prayer you prayed for was answered: %q", request.Title))
+}
+
+// notifyPrayers notifies everyone who prayed for a request, except its
+// owner. Like recordEvent it is best-effort: a failure is logged rather than
+// failing the request.
+func (s *Service) notifyPrayers(ctx context.Context, request *data.PrayerRequest, message string) {
+	identities, err := s.repo.GetPrayerIdentities(ctx, request.ID.Hex())
+	if err != nil {
+		log.Printf("Failed to list who prayed for %s: %v", request.ID.Hex(), err)
+		return
+	}
+	identities = slices.DeleteFunc(identities, func(identity string) bool {
+		return identity == "user:"+request.UserID.Hex()
+	})
+
+	if err := s.notifier.Notify(ctx, identities, notificationData.TypePrayerAnswered, request.ID, message); err != nil {
+		log.Printf("Failed to notify %d people about %s: %v", len(identities), request.ID.Hex(), err)
+	}
+}
@@ -0,0 +1,60 @@
+package prayer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"prayerreq-backend/internal/apierror"
+	"prayerreq-backend/internal/auth"
+	"prayerreq-backend/internal/pagination"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// GetArchive handles GET /api/v1/prayers/archived?user_id=&limit=&cursor=
+// It lists a user's expired prayer requests, the caller's own by default.
+// Someone else's anonymous requests are only listed for staff.
+func (s *Service) GetArchive(w http.ResponseWriter, r *http.Request) {
+	user := auth.UserFromContext(r.Context())
+	userID := user.ID
+	if value := r.URL.Query().Get("user_id"); value != "" {
+		id, err := bson.ObjectIDFromHex(value)
+		if err != nil {
+			apierror.Write(w, r, invalidParameter("user_id must be a valid ID"))
+			return
+		}
+		userID = id
+	}
+	includeAnonymous := userID == user.ID || auth.IsStaff(r.Context())
+
+	page, err := pagination.FromRequest(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	prayers, err := s.repo.GetArchivedPrayerRequests(r.Context(), userID, includeAnonymous, page)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(shownPage(r.Context(), prayers))
+}
+
+// ArchiveExpired archives the prayer requests whose expiry has passed and
+// reports how many
+func (s *Service) ArchiveExpired(ctx context.Context) (int, error) {
+	return s.repo.ArchiveExpiredPrayerRequests(ctx, time.Now())
+}
+
+// checkExpiry rejects an expiry that has already passed
+func checkExpiry(expiresAt *time.Time) error {
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return apierror.BadRequest(CodeInvalidExpiry, "expires_at must be in the future")
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package prayer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"prayerreq-backend/internal/apierror"
+	"prayerreq-backend/internal/auth"
+	"prayerreq-backend/internal/controller/prayer/data"
+	"prayerreq-backend/internal/pagination"
+	"prayerreq-backend/internal/validate"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// UpdateComment handles PUT /api/v1/prayers/{id}/comments/{commentId}
+// Only the author can edit a comment.
+func (s *Service) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	var input data.UpdateCommentInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		apierror.Write(w, r, apierror.InvalidJSON(err))
+		return
+	}
+	if err := validate.Struct(input); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	comment, err := s.getRequestComment(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	if !isCommentAuthor(r, comment) {
+		apierror.Write(w, r, apierror.Forbidden(CodeNotOwner, "Only the author can edit this comment"))
+		return
+	}
+
+	now := time.Now()
+	comment.Message = input.Message
+	comment.EditedAt = &now
+
+	// Like edits of prayer requests, passing the filter doesn't undo a
+	// moderator's decision
+	if screened := s.screen(r.Context(), comment.UserName, comment.Message); !isPublished(screened) {
+		comment.ModerationStatus = screened.ModerationStatus
+		comment.ModerationReasons = screened.ModerationReasons
+	}
+
+	if err := s.repo.UpdateComment(r.Context(), comment.ID.Hex(), comment); err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comment)
+}
+
+// DeleteComment handles DELETE /api/v1/prayers/{id}/comments/{commentId}
+// The author and staff can delete a comment. It is replaced by a tombstone
+// without its text or name, so the replies to it keep their place.
+func (s *Service) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	comment, err := s.getRequestComment(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	if !isCommentAuthor(r, comment) && !auth.IsStaff(r.Context()) {
+		apierror.Write(w, r, apierror.Forbidden(CodeNotOwner, "Only the author can delete this comment"))
+		return
+	}
+
+	now := time.Now()
+	comment.Message = ""
+	comment.UserName = ""
+	comment.DeletedAt = &now
+
+	if err := s.repo.UpdateComment(r.Context(), comment.ID.Hex(), comment); err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getRequestComment looks up the comment addressed by the {id} and
+// {commentId} URL parameters. Deleted comments can't be changed, so they
+// are not found.
+func (s *Service) getRequestComment(r *http.Request) (*data.Comment, error) {
+	request, err := s.getVisiblePrayer(r, chi.URLParam(r, "id"))
+	if err != nil {
+		return nil, prayerNotFound(err)
+	}
+
+	comment, err := s.repo.GetCommentByID(r.Context(), chi.URLParam(r, "commentId"))
+	if err == nil && (comment.PrayerRequestID != request.ID || comment.DeletedAt != nil) {
+		err = mongo.ErrNoDocuments
+	}
+	if err == nil && !isPublished(comment.Moderation) && !isCommentAuthor(r, comment) && !auth.IsStaff(r.Context()) {
+		err = mongo.ErrNoDocuments
+	}
+	if err != nil {
+		return nil, commentNotFound(err)
+	}
+
+	return comment, nil
+}
+
+// getCommentTree gets a page of the top-level comments on a prayer request
+// with their replies nested. Replies to a comment that is hidden by
+// moderation are hidden with it.
+func (s *Service) getCommentTree(ctx context.Context, prayerID string, page pagination.Params) (*pagination.Page[*data.Comment], error) {
+	threads, err := s.repo.GetCommentThreads(ctx, prayerID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[bson.ObjectID]*data.Comment, len(threads.Items))
+	threadIDs := make([]bson.ObjectID, 0, len(threads.Items))
+	for _, thread := range threads.Items {
+		byID[thread.ID] = thread
+		threadIDs = append(threadIDs, thread.ID)
+	}
+
+	replies, err := s.repo.GetCommentReplies(ctx, threadIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replies come oldest first, so a parent is always placed before its replies
+	for _, reply := range replies {
+		parent := byID[*reply.ParentID]
+		if parent == nil {
+			continue
+		}
+		parent.Replies = append(parent.Replies, reply)
+		byID[reply.ID] = reply
+	}
+
+	return threads, nil
+}
+
+// threadOf returns the ID of the thread a reply to the comment belongs to
+func threadOf(parent *data.Comment) bson.ObjectID {
+	if parent.ThreadID.IsZero() {
+		return parent.ID
+	}
+	return parent.ThreadID
+}
+
+// isCommentAuthor reports whether the authenticated user wrote the comment.
+// Comments written without an account can't be changed by anyone.
+func isCommentAuthor(r *http.Request, comment *data.Comment) bool {
+	user := auth.UserFromContext(r.Context())
+	return user != nil && !comment.UserID.IsZero() && comment.UserID == user.ID
+}
@@ -8,83 +8,410 @@ import (
 
 // PrayerRequest represents a prayer request in the system
 type PrayerRequest struct {
-	ID          bson.ObjectID `json:"id" bson:"_id,omitempty"`
-	Title       string        `json:"title" bson:"title"`
-	Description string        `json:"description" bson:"description"`
-	UserID      bson.ObjectID `json:"user_id" bson:"user_id"`
-	UserName    string        `json:"user_name" bson:"user_name"`
-	IsAnonymous bool          `json:"is_anonymous" bson:"is_anonymous"`
-	IsAnswered  bool          `json:"is_answered" bson:"is_answered"`
-	Priority    string        `json:"priority" bson:"priority"` // "low", "medium", "high", "urgent"
-	Category    string        `json:"category" bson:"category"`
-	Tags        []string      `json:"tags" bson:"tags"`
-	PrayCount   int           `json:"pray_count" bson:"pray_count"`
-	CreatedAt   time.Time     `json:"created_at" bson:"created_at"`
-	UpdatedAt   time.Time     `json:"updated_at" bson:"updated_at"`
-}
-
-// Prayer represents a prayer made for a request
-type Prayer struct {
+	ID             bson.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Title          string         `json:"title" bson:"title"`
+	Description    string         `json:"description" bson:"description"`
+	UserID         bson.ObjectID  `json:"-" bson:"user_id"`           // set when written while logged in
+	OwnerID        *bson.ObjectID `json:"user_id,omitempty" bson:"-"` // UserID, only shown to the owner and staff
+	UserName       string         `json:"user_name" bson:"user_name"`
+	IsAnonymous    bool           `json:"is_anonymous" bson:"is_anonymous"`
+	IsAnswered     bool           `json:"is_answered" bson:"is_answered"`
+	AnsweredAt     *time.Time     `json:"answered_at,omitempty" bson:"answered_at,omitempty"`
+	Testimony      string         `json:"testimony,omitempty" bson:"testimony,omitempty"` // how the prayer was answered, in the owner's words
+	AnswerPending  bool           `json:"-" bson:"answer_pending,omitempty"`              // the answer waits for review before it is announced
+	Priority       string         `json:"priority" bson:"priority"`                       // "low", "medium", "high", "urgent"
+	Category       string         `json:"category" bson:"category"`
+	Tags           []string       `json:"tags" bson:"tags"`
+	Location       *Location      `json:"location,omitempty" bson:"location,omitempty"`
+	PrayCount      int            `json:"pray_count" bson:"pray_count"`           // the "pray" reactions, kept apart for sorting and filtering
+	ReactionCounts map[string]int `json:"reaction_counts" bson:"reaction_counts"` // by reaction type
+	SavedCount     int            `json:"saved_count" bson:"saved_count"`
+	CommentCount   int            `json:"comment_count" bson:"comment_count"` // published comments that weren't deleted
+	CreatedAt      time.Time      `json:"created_at" bson:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at" bson:"updated_at"`
+	ExpiresAt      *time.Time     `json:"expires_at,omitempty" bson:"expires_at,omitempty"`   // e.g. the end of a pilgrimage
+	ArchivedAt     *time.Time     `json:"archived_at,omitempty" bson:"archived_at,omitempty"` // set once the request has expired
+	DeletedAt      *time.Time     `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`   // set while the request is in the trash
+	Moderation     `bson:",inline"`
+}
+
+// ShownTo returns a copy of the prayer request as a user sees it. Who wrote
+// a request is only shown to its owner and staff, so nobody else can tie an
+// anonymous request to an account.
+func (p *PrayerRequest) ShownTo(userID bson.ObjectID, staff bool) *PrayerRequest {
+	shown := *p
+	if !p.UserID.IsZero() && (staff || p.UserID == userID) {
+		owner := p.UserID
+		shown.OwnerID = &owner
+	}
+	return &shown
+}
+
+// Moderation is the review state of a prayer request or comment. Content
+// that isn't published is hidden from everyone but its author and the
+// moderators.
+type Moderation struct {
+	ModerationStatus  string     `json:"moderation_status" bson:"moderation_status"`                       // "published", "pending_review", "rejected"
+	ModerationReasons []string   `json:"moderation_reasons,omitempty" bson:"moderation_reasons,omitempty"` // why it was held for review
+	ReportCount       int        `json:"report_count,omitempty" bson:"report_count"`                       // distinct reports since it was last approved
+	PublishedAt       *time.Time `json:"-" bson:"published_at,omitempty"`                                  // when it was first published
+}
+
+// Holy sites a prayer request can be tied to
+const (
+	HolySiteMasjidAlHaram  = "masjid_al_haram"
+	HolySiteMasjidAnNabawi = "masjid_an_nabawi"
+	HolySiteMasjidAlAqsa   = "masjid_al_aqsa"
+	HolySiteArafat         = "arafat"
+	HolySiteMina           = "mina"
+	HolySiteMuzdalifah     = "muzdalifah"
+	HolySiteMasjidQuba     = "masjid_quba"
+)
+
+// Location is where a prayer request is from or should be prayed for.
+// Place is free text; HolySite optionally ties it to a known holy site.
+type Location struct {
+	Place       string       `json:"place" bson:"place" validate:"required,max=100"`
+	HolySite    string       `json:"holy_site,omitempty" bson:"holy_site,omitempty" validate:"omitempty,oneof=masjid_al_haram masjid_an_nabawi masjid_al_aqsa arafat mina muzdalifah masjid_quba"`
+	Coordinates *Coordinates `json:"coordinates,omitempty" bson:"coordinates,omitempty"`
+}
+
+// Coordinates is a point in decimal degrees
+type Coordinates struct {
+	Latitude  float64 `json:"latitude" bson:"latitude" validate:"min=-90,max=90"`
+	Longitude float64 `json:"longitude" bson:"longitude" validate:"min=-180,max=180"`
+}
+
+// Reaction types. Praying is the main one: it is what POST /pray records
+// and what pray_count counts.
+const (
+	ReactionPray          = "pray"
+	ReactionAmeen         = "ameen"
+	ReactionPrayedAtHaram = "prayed_at_haram"
+	ReactionMadeDua       = "made_dua"
+)
+
+// ReactionTypes lists every reaction type
+var ReactionTypes = []string{
+	ReactionPray,
+	ReactionAmeen,
+	ReactionPrayedAtHaram,
+	ReactionMadeDua,
+}
+
+// Reaction records that an identity (a user or an anonymous device) reacted
+// to a prayer request. There is at most one per identity, request and type.
+type Reaction struct {
 	ID              bson.ObjectID `json:"id" bson:"_id,omitempty"`
 	PrayerRequestID bson.ObjectID `json:"prayer_request_id" bson:"prayer_request_id"`
+	Type            string        `json:"type" bson:"type"`
 	UserID          bson.ObjectID `json:"user_id" bson:"user_id"`
 	UserName        string        `json:"user_name" bson:"user_name"`
-	Message         string        `json:"message" bson:"message"`
-	IsAnonymous     bool          `json:"is_anonymous" bson:"is_anonymous"`
+	Identity        string        `json:"-" bson:"identity"` // "user:<id>" or "device:<token>"
+	CreatedAt       time.Time     `json:"created_at" bson:"created_at"`
+}
+
+// ReactionInput represents input for reacting to a prayer request
+type ReactionInput struct {
+	Type string `json:"type" validate:"required,oneof=pray ameen prayed_at_haram made_dua"`
+}
+
+// ReactionResult represents the outcome of adding or removing a reaction
+type ReactionResult struct {
+	Type           string         `json:"type"`
+	Reacted        bool           `json:"reacted"` // whether the caller now has this reaction
+	Counted        bool           `json:"counted"` // false when nothing changed
+	ReactionCounts map[string]int `json:"reaction_counts"`
+}
+
+// ReactionStatus tells the caller how they reacted to a prayer request
+type ReactionStatus struct {
+	Reactions      []string       `json:"reactions"`
+	ReactionCounts map[string]int `json:"reaction_counts"`
+}
+
+// PrayResult represents the outcome of praying for a request
+type PrayResult struct {
+	Message   string `json:"message"`
+	Prayed    bool   `json:"prayed"`
+	Counted   bool   `json:"counted"` // false when the caller had already prayed
+	PrayCount int    `json:"pray_count"`
+}
+
+// PrayedStatus tells the caller whether they already prayed for a request
+type PrayedStatus struct {
+	Prayed bool `json:"prayed"`
+}
+
+// SavedPrayer records that a user saved a prayer request to their list.
+// There is at most one per user and request.
+type SavedPrayer struct {
+	ID              bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	PrayerRequestID bson.ObjectID `json:"prayer_request_id" bson:"prayer_request_id"`
+	UserID          bson.ObjectID `json:"user_id" bson:"user_id"`
 	CreatedAt       time.Time     `json:"created_at" bson:"created_at"`
 }
 
+// SavedPrayerRequest is a prayer request in a user's saved list
+type SavedPrayerRequest struct {
+	PrayerRequest `bson:",inline"`
+	SavedAt       time.Time     `json:"saved_at" bson:"saved_at"`
+	SaveID        bson.ObjectID `json:"-" bson:"save_id"`
+}
+
+// SaveResult represents the outcome of saving or unsaving a request
+type SaveResult struct {
+	Saved      bool `json:"saved"`
+	SavedCount int  `json:"saved_count"`
+}
+
+// Sort orders for listing prayer requests. A leading "-" sorts descending.
+const (
+	SortNewest         = "-created_at"
+	SortOldest         = "created_at"
+	SortMostPrayed     = "-pray_count"
+	SortLeastPrayed    = "pray_count"
+	SortRecentlyEdited = "-updated_at"
+	SortLeastEdited    = "updated_at"
+)
+
+// PrayerFilter narrows and orders a listing of prayer requests. Zero
+// values don't filter, except that archived requests are left out unless
+// IncludeArchived is set; every tag must be present on a request.
+type PrayerFilter struct {
+	Category         string         `json:"category" validate:"max=50"`
+	Priority         string         `json:"priority" validate:"omitempty,oneof=low medium high urgent"`
+	Tags             []string       `json:"tag" validate:"max=10,dive,required,max=30"`
+	IsAnswered       *bool          `json:"is_answered"`
+	UserID           *bson.ObjectID `json:"user_id"`
+	ExcludeAnonymous bool           `json:"-"` // set when the caller may not see a user's anonymous requests
+	CreatedAfter     *time.Time     `json:"created_after"`
+	CreatedBefore    *time.Time     `json:"created_before"`
+	MinPrayCount     int            `json:"min_pray_count" validate:"min=0"`
+	HolySite         string         `json:"holy_site" validate:"omitempty,oneof=masjid_al_haram masjid_an_nabawi masjid_al_aqsa arafat mina muzdalifah masjid_quba"`
+	Place            string         `json:"place" validate:"max=100"` // matched case-insensitively
+	IncludeArchived  bool           `json:"include_archived"`
+	Sort             string         `json:"sort" validate:"omitempty,oneof=-created_at created_at -pray_count pray_count -updated_at updated_at"`
+}
+
+// SearchQuery represents a full-text search with optional filters. Text
+// uses MongoDB text search syntax: words match any of the terms, "quoted
+// phrases" must all match and -words exclude results.
+type SearchQuery struct {
+	Text            string `json:"q" validate:"required,max=200"`
+	Category        string `json:"category" validate:"max=50"`
+	Priority        string `json:"priority" validate:"omitempty,oneof=low medium high urgent"`
+	IsAnswered      *bool  `json:"is_answered"`
+	IncludeArchived bool   `json:"include_archived"`
+}
+
+// SearchResult is a prayer request matched by a search, with its relevance
+type SearchResult struct {
+	PrayerRequest `bson:",inline"`
+	Score         float64 `json:"score" bson:"score"`
+}
+
 // CreatePrayerRequestInput represents input for creating a prayer request
 type CreatePrayerRequestInput struct {
-	Title       string   `json:"title" validate:"required"`
-	Description string   `json:"description" validate:"required"`
-	UserName    string   `json:"user_name"`
-	IsAnonymous bool     `json:"is_anonymous"`
-	Priority    string   `json:"priority"`
-	Category    string   `json:"category"`
-	Tags        []string `json:"tags"`
+	Title       string     `json:"title" validate:"required,max=200"`
+	Description string     `json:"description" validate:"required,max=5000"`
+	UserName    string     `json:"user_name" validate:"max=100"`
+	IsAnonymous bool       `json:"is_anonymous"`
+	Priority    string     `json:"priority" validate:"omitempty,oneof=low medium high urgent"`
+	Category    string     `json:"category" validate:"max=50"`
+	Tags        []string   `json:"tags" validate:"max=10,dive,required,max=30"`
+	Location    *Location  `json:"location"`
+	ExpiresAt   *time.Time `json:"expires_at"`
 }
 
 // UpdatePrayerRequestInput represents input for updating a prayer request
 type UpdatePrayerRequestInput struct {
-	Title       *string  `json:"title"`
-	Description *string  `json:"description"`
-	IsAnswered  *bool    `json:"is_answered"`
-	Priority    *string  `json:"priority"`
-	Category    *string  `json:"category"`
-	Tags        []string `json:"tags"`
+	Title       *string    `json:"title" validate:"omitempty,required,max=200"`
+	Description *string    `json:"description" validate:"omitempty,required,max=5000"`
+	Priority    *string    `json:"priority" validate:"omitempty,oneof=low medium high urgent"`
+	Category    *string    `json:"category" validate:"omitempty,max=50"`
+	Tags        []string   `json:"tags" validate:"max=10,dive,required,max=30"`
+	Location    *Location  `json:"location"`
+	ExpiresAt   *time.Time `json:"expires_at"` // a future time; also takes an archived request out of the archive
 }
 
-// Comment represents a comment/message on a prayer request
+// PrayerRequestUpdate lists the fields an edit or answer changes; nil fields
+// are left alone. Only these fields are written, so the counters kept with
+// increments and a moderator's decision made meanwhile aren't overwritten.
+type PrayerRequestUpdate struct {
+	Title             *string
+	Description       *string
+	Priority          *string
+	Category          *string
+	Tags              []string
+	Location          *Location
+	ExpiresAt         *time.Time // also takes the request out of the archive
+	AnsweredAt        *time.Time // also marks the request answered
+	Testimony         *string
+	AnswerPending     *bool
+	ModerationStatus  *string   // set along with ModerationReasons
+	ModerationReasons []string  // only written with ModerationStatus
+	UpdatedAt         time.Time // left as it is when zero
+}
+
+// Apply makes the update to a prayer request
+func (u *PrayerRequestUpdate) Apply(p *PrayerRequest) {
+	if u.Title != nil {
+		p.Title = *u.Title
+	}
+	if u.Description != nil {
+		p.Description = *u.Description
+	}
+	if u.Priority != nil {
+		p.Priority = *u.Priority
+	}
+	if u.Category != nil {
+		p.Category = *u.Category
+	}
+	if u.Tags != nil {
+		p.Tags = u.Tags
+	}
+	if u.Location != nil {
+		p.Location = u.Location
+	}
+	if u.ExpiresAt != nil {
+		p.ExpiresAt = u.ExpiresAt
+		p.ArchivedAt = nil
+	}
+	if u.AnsweredAt != nil {
+		p.IsAnswered = true
+		p.AnsweredAt = u.AnsweredAt
+	}
+	if u.Testimony != nil {
+		p.Testimony = *u.Testimony
+	}
+	if u.AnswerPending != nil {
+		p.AnswerPending = *u.AnswerPending
+	}
+	if u.ModerationStatus != nil {
+		p.ModerationStatus = *u.ModerationStatus
+		p.ModerationReasons = u.ModerationReasons
+	}
+	if !u.UpdatedAt.IsZero() {
+		p.UpdatedAt = u.UpdatedAt
+	}
+}
+
+// AnswerPrayerInput represents input for marking a prayer request answered
+type AnswerPrayerInput struct {
+	Testimony *string `json:"testimony" validate:"omitempty,max=5000"` // left as it is when omitted
+}
+
+// Comment represents a comment/message on a prayer request. Replies point
+// at their parent and share the ID of the top-level comment of their thread.
+// Deleted comments are kept as tombstones, without their text, so the
+// replies to them stay in place.
 type Comment struct {
+	ID              bson.ObjectID  `json:"id" bson:"_id,omitempty"`
+	PrayerRequestID bson.ObjectID  `json:"prayer_request_id" bson:"prayer_request_id"`
+	ParentID        *bson.ObjectID `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	ThreadID        bson.ObjectID  `json:"-" bson:"thread_id,omitempty"`
+	UserID          bson.ObjectID  `json:"-" bson:"user_id,omitempty"` // set when written while logged in
+	UserName        string         `json:"user_name" bson:"user_name"`
+	Message         string         `json:"message" bson:"message"`
+	IsAnonymous     bool           `json:"is_anonymous" bson:"is_anonymous"`
+	CreatedAt       time.Time      `json:"created_at" bson:"created_at"`
+	EditedAt        *time.Time     `json:"edited_at,omitempty" bson:"edited_at,omitempty"`
+	DeletedAt       *time.Time     `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	Replies         []*Comment     `json:"replies,omitempty" bson:"-"` // only filled in the tree view
+	Moderation      `bson:",inline"`
+}
+
+// Views of the comments on a prayer request
+const (
+	CommentViewFlat = "flat" // every comment, oldest first
+	CommentViewTree = "tree" // top-level comments, oldest first, with their replies nested
+)
+
+// Targets of a report
+const (
+	ReportTargetPrayer  = "prayer_request"
+	ReportTargetComment = "comment"
+)
+
+// Report flags a prayer request or comment as abusive. There is at most one
+// per identity and target.
+type Report struct {
 	ID              bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	TargetType      string        `json:"target_type" bson:"target_type"` // "prayer_request" or "comment"
+	TargetID        bson.ObjectID `json:"target_id" bson:"target_id"`
 	PrayerRequestID bson.ObjectID `json:"prayer_request_id" bson:"prayer_request_id"`
-	UserName        string        `json:"user_name" bson:"user_name"`
-	Message         string        `json:"message" bson:"message"`
-	IsAnonymous     bool          `json:"is_anonymous" bson:"is_anonymous"`
+	Reason          string        `json:"reason" bson:"reason"` // "spam", "abuse", "inappropriate", "other"
+	Details         string        `json:"details,omitempty" bson:"details,omitempty"`
+	Identity        string        `json:"-" bson:"identity"`
 	CreatedAt       time.Time     `json:"created_at" bson:"created_at"`
 }
 
+// ReportInput represents input for reporting a prayer request or comment
+type ReportInput struct {
+	Reason  string `json:"reason" validate:"required,oneof=spam abuse inappropriate other"`
+	Details string `json:"details" validate:"max=1000"`
+}
+
+// ReportResult represents the outcome of reporting content
+type ReportResult struct {
+	Reported bool `json:"reported"`
+	Counted  bool `json:"counted"` // false when the caller had already reported it
+}
+
 // PrayerStats represents prayer request statistics
 type PrayerStats struct {
 	TotalPrayers    int            `json:"total_prayers"`
 	TotalPrayCount  int            `json:"total_pray_count"`
+	TotalComments   int            `json:"total_comments"`
 	AnsweredPrayers int            `json:"answered_prayers"`
 	UrgentPrayers   int            `json:"urgent_prayers"`
 	CategoriesCount map[string]int `json:"categories_count"`
+	LocationsCount  map[string]int `json:"locations_count"` // by holy site, or by lower-cased place for other locations
 	RecentActivity  []ActivityItem `json:"recent_activity"`
 }
 
-// ActivityItem represents a recent activity item
+// Activity types
+const (
+	ActivityPrayerCreated      = "prayer_created"
+	ActivityPrayerAnswered     = "prayer_answered"
+	ActivityPrayCountIncreased = "pray_count_increased"
+	ActivityCommentAdded       = "comment_added"
+)
+
+// ActivityTypes lists every known activity type
+var ActivityTypes = []string{
+	ActivityPrayerCreated,
+	ActivityPrayerAnswered,
+	ActivityPrayCountIncreased,
+	ActivityCommentAdded,
+}
+
+// PrayCountEvent is streamed when a prayer request's count goes up
+type PrayCountEvent struct {
+	PrayerRequestID bson.ObjectID `json:"prayer_request_id"`
+	PrayCount       int           `json:"pray_count"`
+}
+
+// ActivityItem represents an entry in the activity event log
 type ActivityItem struct {
-	Type      string    `json:"type"` // "prayer_created", "prayer_answered", "pray_count_increased"
-	Message   string    `json:"message"`
-	CreatedAt time.Time `json:"created_at"`
+	ID              bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	Type            string        `json:"type" bson:"type"` // "prayer_created", "prayer_answered", "pray_count_increased", "comment_added"
+	PrayerRequestID bson.ObjectID `json:"prayer_request_id" bson:"prayer_request_id"`
+	Message         string        `json:"message" bson:"message"`
+	CreatedAt       time.Time     `json:"created_at" bson:"created_at"`
+	DeletedAt       *time.Time    `json:"-" bson:"deleted_at,omitempty"` // hidden with its trashed prayer request
 }
 
 // CreateCommentInput represents input for creating a comment
 type CreateCommentInput struct {
-	Message     string `json:"message" validate:"required"`
-	UserName    string `json:"user_name"`
-	IsAnonymous bool   `json:"is_anonymous"`
+	ParentID    *bson.ObjectID `json:"parent_id"` // the comment replied to
+	Message     string         `json:"message" validate:"required,max=2000"`
+	UserName    string         `json:"user_name" validate:"max=100"`
+	IsAnonymous bool           `json:"is_anonymous"`
+}
+
+// UpdateCommentInput represents input for editing a comment
+type UpdateCommentInput struct {
+	Message string `json:"message" validate:"required,max=2000"`
 }
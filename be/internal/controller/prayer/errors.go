@@ -0,0 +1,23 @@
+package prayer
+
+import "prayerreq-backend/internal/apierror"
+
+// Error codes returned by the prayer endpoints
+const (
+	CodePrayerNotFound      = "prayer_not_found"
+	CodeNotOwner            = "not_owner"
+	CodeMissingQuery        = "missing_query"
+	CodeUnknownActivityType = "unknown_activity_type"
+	CodeInvalidExpiry       = "invalid_expiry"
+	CodeCommentNotFound     = "comment_not_found"
+)
+
+// prayerNotFound maps an error from looking up a prayer request by ID
+func prayerNotFound(err error) *apierror.Error {
+	return apierror.FromRepository(err, CodePrayerNotFound, "Prayer request not found")
+}
+
+// commentNotFound maps an error from looking up a comment by ID
+func commentNotFound(err error) *apierror.Error {
+	return apierror.FromRepository(err, CodeCommentNotFound, "Comment not found")
+}
@@ -0,0 +1,114 @@
+package prayer
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"prayerreq-backend/internal/apierror"
+	"prayerreq-backend/internal/auth"
+	"prayerreq-backend/internal/controller/prayer/data"
+	"prayerreq-backend/internal/validate"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// parsePrayerFilter reads the filter and sort query parameters of GET /prayers
+func parsePrayerFilter(r *http.Request) (data.PrayerFilter, error) {
+	query := r.URL.Query()
+	filter := data.PrayerFilter{
+		Category: query.Get("category"),
+		Priority: query.Get("priority"),
+		Tags:     splitValues(query, "tag"),
+		HolySite: query.Get("holy_site"),
+		Place:    strings.TrimSpace(query.Get("place")),
+		Sort:     query.Get("sort"),
+	}
+
+	if value := query.Get("is_answered"); value != "" {
+		isAnswered, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, invalidParameter("is_answered must be true or false")
+		}
+		filter.IsAnswered = &isAnswered
+	}
+	if value := query.Get("include_archived"); value != "" {
+		includeArchived, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, invalidParameter("include_archived must be true or false")
+		}
+		filter.IncludeArchived = includeArchived
+	}
+	if value := query.Get("user_id"); value != "" {
+		userID, err := bson.ObjectIDFromHex(value)
+		if err != nil {
+			return filter, invalidParameter("user_id must be a valid ID")
+		}
+		filter.UserID = &userID
+
+		// Listing someone else's requests mustn't reveal which anonymous
+		// ones they wrote
+		user := auth.UserFromContext(r.Context())
+		filter.ExcludeAnonymous = (user == nil || user.ID != userID) && !auth.IsStaff(r.Context())
+	}
+	for name, target := range map[string]**time.Time{
+		"created_after":  &filter.CreatedAfter,
+		"created_before": &filter.CreatedBefore,
+	} {
+		if value := query.Get(name); value != "" {
+			t, err := parseTime(value)
+			if err != nil {
+				return filter, invalidParameter(name + " must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+			}
+			*target = &t
+		}
+	}
+	if value := query.Get("min_pray_count"); value != "" {
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, invalidParameter("min_pray_count must be an integer")
+		}
+		filter.MinPrayCount = count
+	}
+
+	if err := validate.Struct(filter); err != nil {
+		return filter, err
+	}
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+		return filter, invalidParameter("created_after must be before created_before")
+	}
+	if filter.Sort == "" {
+		filter.Sort = data.SortNewest
+	}
+
+	return filter, nil
+}
+
+// splitValues returns the values of a repeatable query parameter, also
+// splitting comma separated values and dropping blank ones
+func splitValues(query url.Values, name string) []string {
+	var values []string
+	for _, value := range query[name] {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// parseTime accepts an RFC 3339 timestamp or a date, which means midnight UTC
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+// invalidParameter reports a query parameter that couldn't be parsed
+func invalidParameter(message string) *apierror.Error {
+	return apierror.BadRequest(apierror.CodeInvalidParameter, message)
+}
@@ -0,0 +1,281 @@
+package prayer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"prayerreq-backend/internal/apierror"
+	"prayerreq-backend/internal/auth"
+	"prayerreq-backend/internal/controller/prayer/data"
+	"prayerreq-backend/internal/moderation"
+	"prayerreq-backend/internal/pagination"
+	"prayerreq-backend/internal/validate"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// ReportPrayer handles POST /api/v1/prayers/{id}/report
+func (s *Service) ReportPrayer(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	report, err := parseReport(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	request, err := s.repo.GetPrayerRequestByID(r.Context(), id)
+	if err == nil && !isPublished(request.Moderation) {
+		err = mongo.ErrNoDocuments
+	}
+	if err != nil {
+		apierror.Write(w, r, prayerNotFound(err))
+		return
+	}
+
+	report.TargetType = data.ReportTargetPrayer
+	report.TargetID = request.ID
+	report.PrayerRequestID = request.ID
+	s.report(w, r, report)
+}
+
+// ReportComment handles POST /api/v1/comments/{id}/report
+func (s *Service) ReportComment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	report, err := parseReport(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	comment, err := s.repo.GetCommentByID(r.Context(), id)
+	if err == nil && (comment.DeletedAt != nil || !isPublished(comment.Moderation)) {
+		err = mongo.ErrNoDocuments
+	}
+	if err == nil {
+		// Comments are hidden along with their prayer request
+		var request *data.PrayerRequest
+		request, err = s.repo.GetPrayerRequestByID(r.Context(), comment.PrayerRequestID.Hex())
+		if err == nil && !isPublished(request.Moderation) {
+			err = mongo.ErrNoDocuments
+		}
+	}
+	if err != nil {
+		apierror.Write(w, r, commentNotFound(err))
+		return
+	}
+
+	report.TargetType = data.ReportTargetComment
+	report.TargetID = comment.ID
+	report.PrayerRequestID = comment.PrayerRequestID
+	s.report(w, r, report)
+}
+
+// parseReport reads a report from the request body. Reports need an
+// identity so each reporter is counted once.
+func parseReport(r *http.Request) (*data.Report, error) {
+	identity, err := auth.Identity(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var input data.ReportInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, apierror.InvalidJSON(err)
+	}
+	if err := validate.Struct(input); err != nil {
+		return nil, err
+	}
+
+	return &data.Report{
+		ID:        bson.NewObjectID(),
+		Reason:    input.Reason,
+		Details:   input.Details,
+		Identity:  identity,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// report records a report on published content. Each identity is counted
+// once per target; enough reports hold the content for review.
+func (s *Service) report(w http.ResponseWriter, r *http.Request, report *data.Report) {
+	counted, err := s.repo.CreateReport(r.Context(), report)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	if counted {
+		id := report.TargetID.Hex()
+		reports, err := s.repo.IncrementReportCount(r.Context(), report.TargetType, id)
+		if err != nil {
+			apierror.Write(w, r, apierror.Internal(err))
+			return
+		}
+		if s.moderator.HoldOnReports(reports) {
+			err := s.repo.SetModerationStatus(r.Context(), report.TargetType, id,
+				moderation.StatusPendingReview, []string{moderation.ReasonReported}, time.Now())
+			if err != nil {
+				apierror.Write(w, r, apierror.Internal(err))
+				return
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data.ReportResult{Reported: true, Counted: counted})
+}
+
+// GetPrayerRequest retrieves a prayer request whatever its moderation status
+func (s *Service) GetPrayerRequest(ctx context.Context, id string) (*data.PrayerRequest, error) {
+	return s.repo.GetPrayerRequestByID(ctx, id)
+}
+
+// GetComment retrieves a comment whatever its moderation status
+func (s *Service) GetComment(ctx context.Context, id string) (*data.Comment, error) {
+	return s.repo.GetCommentByID(ctx, id)
+}
+
+// GetPrayerRequestsByStatus lists the prayer requests in a moderation status
+func (s *Service) GetPrayerRequestsByStatus(ctx context.Context, status string, page pagination.Params) (*pagination.Page[*data.PrayerRequest], error) {
+	requests, err := s.repo.GetPrayerRequestsByStatus(ctx, status, page)
+	if err != nil {
+		return nil, err
+	}
+	return shownPage(ctx, requests), nil
+}
+
+// GetCommentsByStatus lists the comments in a moderation status
+func (s *Service) GetCommentsByStatus(ctx context.Context, status string, page pagination.Params) (*pagination.Page[*data.Comment], error) {
+	return s.repo.GetCommentsByStatus(ctx, status, page)
+}
+
+// GetReports lists the reports on a prayer request or comment
+func (s *Service) GetReports(ctx context.Context, target, id string, page pagination.Params) (*pagination.Page[*data.Report], error) {
+	return s.repo.GetReports(ctx, target, id, page)
+}
+
+// ModeratePrayerRequest applies a moderator's decision to a prayer request.
+// A request published for the first time is announced like a new one, and
+// an answer held for review is announced once the request is approved.
+func (s *Service) ModeratePrayerRequest(ctx context.Context, id, status string) (*data.PrayerRequest, error) {
+	request, err := s.repo.GetPrayerRequestByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	firstPublished := status == moderation.StatusPublished && request.PublishedAt == nil
+	if err := s.setModerationStatus(ctx, data.ReportTargetPrayer, id, status, &request.Moderation); err != nil {
+		return nil, err
+	}
+
+	if firstPublished {
+		s.recordEvent(ctx, data.ActivityPrayerCreated, request,
+			fmt.Sprintf("New prayer request: %q", request.Title), request)
+	}
+	if status == moderation.StatusPublished && request.AnswerPending {
+		announced := false
+		if _, err := s.repo.UpdatePrayerRequest(ctx, id, &data.PrayerRequestUpdate{AnswerPending: &announced}); err != nil {
+			return nil, err
+		}
+		request.AnswerPending = false
+		s.announceAnswer(ctx, request)
+	}
+
+	return request, nil
+}
+
+// ModerateComment applies a moderator's decision to a comment. A comment
+// published for the first time is announced like a new one.
+func (s *Service) ModerateComment(ctx context.Context, id, status string) (*data.Comment, error) {
+	comment, err := s.repo.GetCommentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	firstPublished := status == moderation.StatusPublished && comment.PublishedAt == nil
+	if err := s.setModerationStatus(ctx, data.ReportTargetComment, id, status, &comment.Moderation); err != nil {
+		return nil, err
+	}
+
+	// Comments on requests that have since been trashed stay quiet
+	if firstPublished {
+		if request, err := s.repo.GetPrayerRequestByID(ctx, comment.PrayerRequestID.Hex()); err == nil {
+			s.recordEvent(ctx, data.ActivityCommentAdded, request,
+				fmt.Sprintf("New comment on %q", request.Title), comment)
+		}
+	}
+
+	return comment, nil
+}
+
+// setModerationStatus stores a moderator's decision and applies it to m.
+// Approving content clears the reasons it was held for; rejecting keeps them.
+func (s *Service) setModerationStatus(ctx context.Context, target, id, status string, m *data.Moderation) error {
+	now := time.Now()
+	reasons := m.ModerationReasons
+	if status == moderation.StatusPublished {
+		reasons = nil
+	}
+
+	if err := s.repo.SetModerationStatus(ctx, target, id, status, reasons, now); err != nil {
+		return err
+	}
+
+	m.ModerationStatus = status
+	m.ModerationReasons = reasons
+	if status == moderation.StatusPublished {
+		m.ReportCount = 0
+		if m.PublishedAt == nil {
+			m.PublishedAt = &now
+		}
+	}
+	return nil
+}
+
+// screen runs new content through the moderation filter and returns the
+// state it starts in
+func (s *Service) screen(ctx context.Context, texts ...string) data.Moderation {
+	status, reasons := s.moderator.Screen(ctx, texts...)
+
+	m := data.Moderation{ModerationStatus: status, ModerationReasons: reasons}
+	if status == moderation.StatusPublished {
+		now := time.Now()
+		m.PublishedAt = &now
+	}
+	return m
+}
+
+// prayerText returns the user-written text of a prayer request
+func prayerText(prayer *data.PrayerRequest) []string {
+	texts := append([]string{prayer.Title, prayer.Description, prayer.UserName}, prayer.Tags...)
+	if prayer.Location != nil {
+		texts = append(texts, prayer.Location.Place)
+	}
+	return texts
+}
+
+// getVisiblePrayer looks up a prayer request the caller may see. Requests
+// held for review or rejected are only shown to their owner and staff; to
+// everyone else they don't exist.
+func (s *Service) getVisiblePrayer(r *http.Request, id string) (*data.PrayerRequest, error) {
+	prayer, err := s.repo.GetPrayerRequestByID(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if !isPublished(prayer.Moderation) && !isOwner(r, prayer) && !auth.IsStaff(r.Context()) {
+		return nil, mongo.ErrNoDocuments
+	}
+	return prayer, nil
+}
+
+// isPublished reports whether content passed moderation
+func isPublished(m data.Moderation) bool {
+	return m.ModerationStatus == moderation.StatusPublished
+}
@@ -0,0 +1,186 @@
+package prayer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"prayerreq-backend/internal/apierror"
+	"prayerreq-backend/internal/auth"
+	"prayerreq-backend/internal/controller/prayer/data"
+	"prayerreq-backend/internal/validate"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// AddReaction handles POST /api/v1/prayers/{id}/reactions
+// Each identity can leave every type of reaction once per request.
+func (s *Service) AddReaction(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	identity, err := auth.Identity(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	var input data.ReactionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		apierror.Write(w, r, apierror.InvalidJSON(err))
+		return
+	}
+	if err := validate.Struct(input); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	request, err := s.getVisiblePrayer(r, id)
+	if err != nil {
+		apierror.Write(w, r, prayerNotFound(err))
+		return
+	}
+
+	counted, err := s.react(r, request, identity, input.Type)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data.ReactionResult{
+		Type:           input.Type,
+		Reacted:        true,
+		Counted:        counted,
+		ReactionCounts: request.ReactionCounts,
+	})
+}
+
+// RemoveReaction handles DELETE /api/v1/prayers/{id}/reactions/{type}
+// Removing the "pray" reaction takes the prayer back out of pray_count.
+func (s *Service) RemoveReaction(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	reactionType := chi.URLParam(r, "type")
+
+	identity, err := auth.Identity(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	if !slices.Contains(data.ReactionTypes, reactionType) {
+		apierror.Write(w, r, invalidParameter("type must be one of: "+strings.Join(data.ReactionTypes, ", ")))
+		return
+	}
+
+	request, err := s.getVisiblePrayer(r, id)
+	if err != nil {
+		apierror.Write(w, r, prayerNotFound(err))
+		return
+	}
+
+	removed, err := s.repo.RemoveReaction(r.Context(), id, reactionType, identity)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	if removed {
+		if err := s.repo.IncrementReactionCount(r.Context(), id, reactionType, -1); err != nil {
+			apierror.Write(w, r, apierror.Internal(err))
+			return
+		}
+		countReaction(request, reactionType, -1)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data.ReactionResult{
+		Type:           reactionType,
+		Reacted:        false,
+		Counted:        removed,
+		ReactionCounts: request.ReactionCounts,
+	})
+}
+
+// GetReactions handles GET /api/v1/prayers/{id}/reactions
+// Callers without an identity get the counts and no reactions of their own.
+func (s *Service) GetReactions(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	request, err := s.getVisiblePrayer(r, id)
+	if err != nil {
+		apierror.Write(w, r, prayerNotFound(err))
+		return
+	}
+
+	status := data.ReactionStatus{
+		Reactions:      []string{},
+		ReactionCounts: request.ReactionCounts,
+	}
+	if identity, err := auth.Identity(r); err == nil {
+		reactions, err := s.repo.GetIdentityReactions(r.Context(), id, identity)
+		if err != nil {
+			apierror.Write(w, r, apierror.Internal(err))
+			return
+		}
+		// In the order the types are defined
+		for _, reactionType := range data.ReactionTypes {
+			if slices.Contains(reactions, reactionType) {
+				status.Reactions = append(status.Reactions, reactionType)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// react records an identity's reaction to a prayer request and reports
+// whether it is new. New reactions are counted on the request, and a new
+// prayer is announced on the activity feed and the live stream.
+func (s *Service) react(r *http.Request, request *data.PrayerRequest, identity, reactionType string) (bool, error) {
+	reaction := &data.Reaction{
+		ID:              bson.NewObjectID(),
+		PrayerRequestID: request.ID,
+		Type:            reactionType,
+		Identity:        identity,
+		CreatedAt:       time.Now(),
+	}
+	if user := auth.UserFromContext(r.Context()); user != nil {
+		reaction.UserID = user.ID
+		reaction.UserName = user.Name
+	}
+
+	added, err := s.repo.AddReaction(r.Context(), reaction)
+	if err != nil || !added {
+		return false, err
+	}
+
+	if err := s.repo.IncrementReactionCount(r.Context(), request.ID.Hex(), reactionType, 1); err != nil {
+		return false, err
+	}
+	countReaction(request, reactionType, 1)
+
+	if reactionType == data.ReactionPray {
+		s.recordEvent(r.Context(), data.ActivityPrayCountIncreased, request,
+			fmt.Sprintf("Someone prayed for %q", request.Title),
+			data.PrayCountEvent{PrayerRequestID: request.ID, PrayCount: request.PrayCount})
+	}
+
+	return true, nil
+}
+
+// countReaction applies a change in a reaction's count to a loaded prayer
+// request the way IncrementReactionCount applies it to the stored one
+func countReaction(request *data.PrayerRequest, reactionType string, delta int) {
+	if request.ReactionCounts == nil {
+		request.ReactionCounts = make(map[string]int)
+	}
+	request.ReactionCounts[reactionType] += delta
+	if reactionType == data.ReactionPray {
+		request.PrayCount += delta
+	}
+}
@@ -0,0 +1,999 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"prayerreq-backend/internal/controller/prayer/data"
+	"prayerreq-backend/internal/moderation"
+	"prayerreq-backend/internal/pagination"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// memoryRepository implements Repository interface in memory.
+// It mirrors the behaviour of mongoRepository: results are ordered the way
+// the Mongo queries sort them, missing documents yield mongo.ErrNoDocuments
+// and malformed IDs fail the same way ObjectIDFromHex does.
+type memoryRepository struct {
+	mu         sync.RWMutex
+	requests   []*data.PrayerRequest
+	comments   []*data.Comment
+	reactions  []*data.Reaction
+	saved      []*data.SavedPrayer
+	activities []*data.ActivityItem
+	reports    []*data.Report
+}
+
+// NewMemoryRepository creates a new in-memory repository for prayers
+func NewMemoryRepository() Repository {
+	return &memoryRepository{}
+}
+
+// CreatePrayerRequest creates a new prayer request
+func (r *memoryRepository) CreatePrayerRequest(ctx context.Context, req *data.PrayerRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := copyPrayerRequest(req)
+	if stored.ID.IsZero() {
+		stored.ID = bson.NewObjectID()
+	}
+	if r.indexOf(stored.ID) >= 0 {
+		return fmt.Errorf("duplicate key: prayer request %s already exists", stored.ID.Hex())
+	}
+
+	r.requests = append(r.requests, stored)
+	return nil
+}
+
+// GetPrayerRequestByID retrieves a prayer request by ID
+func (r *memoryRepository) GetPrayerRequestByID(ctx context.Context, id string) (*data.PrayerRequest, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.indexOf(objectID)
+	if i < 0 || r.requests[i].DeletedAt != nil {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	return copyPrayerRequest(r.requests[i]), nil
+}
+
+// GetPrayerRequests retrieves a page of prayer requests matching the filter
+func (r *memoryRepository) GetPrayerRequests(ctx context.Context, filter data.PrayerFilter, page pagination.Params) (*pagination.Page[*data.PrayerRequest], error) {
+	sort, order := prayerRequestOrder(filter.Sort)
+	requests := r.findPrayerRequests(func(req *data.PrayerRequest) bool {
+		return matchesPrayerFilter(req, filter)
+	})
+	return pagination.Slice(requests, page, order.Desc, sortedPrayerRequestCursor(sort)), nil
+}
+
+// UpdatePrayerRequest sets the fields an edit or answer changes and returns
+// the updated prayer request
+func (r *memoryRepository) UpdatePrayerRequest(ctx context.Context, id string, update *data.PrayerRequestUpdate) (*data.PrayerRequest, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(objectID)
+	if i < 0 || r.requests[i].DeletedAt != nil {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	update.Apply(r.requests[i])
+	r.requests[i] = copyPrayerRequest(r.requests[i])
+	return copyPrayerRequest(r.requests[i]), nil
+}
+
+// AddReaction records that an identity reacted to a request. It reports
+// false, without recording anything, if the identity had already reacted
+// this way.
+func (r *memoryRepository) AddReaction(ctx context.Context, reaction *data.Reaction) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.reactionIndexOf(reaction.PrayerRequestID, reaction.Type, reaction.Identity) >= 0 {
+		return false, nil
+	}
+
+	stored := *reaction
+	if stored.ID.IsZero() {
+		stored.ID = bson.NewObjectID()
+	}
+
+	r.reactions = append(r.reactions, &stored)
+	return true, nil
+}
+
+// RemoveReaction removes an identity's reaction of a type from a request.
+// It reports false if there was none.
+func (r *memoryRepository) RemoveReaction(ctx context.Context, prayerID, reactionType, identity string) (bool, error) {
+	objectID, err := bson.ObjectIDFromHex(prayerID)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.reactionIndexOf(objectID, reactionType, identity)
+	if i < 0 {
+		return false, nil
+	}
+
+	r.reactions = slices.Delete(r.reactions, i, i+1)
+	return true, nil
+}
+
+// IncrementReactionCount adds delta to the count of a reaction type on a
+// prayer request. The pray count moves with the "pray" reactions.
+func (r *memoryRepository) IncrementReactionCount(ctx context.Context, id, reactionType string, delta int) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i := r.indexOf(objectID); i >= 0 && r.requests[i].DeletedAt == nil {
+		req := r.requests[i]
+		if req.ReactionCounts == nil {
+			req.ReactionCounts = make(map[string]int)
+		}
+		req.ReactionCounts[reactionType] += delta
+		if reactionType == data.ReactionPray {
+			req.PrayCount += delta
+		}
+	}
+	return nil
+}
+
+// GetIdentityReactions returns the types of reaction an identity left on a request
+func (r *memoryRepository) GetIdentityReactions(ctx context.Context, prayerID, identity string) ([]string, error) {
+	objectID, err := bson.ObjectIDFromHex(prayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var types []string
+	for _, reaction := range r.reactions {
+		if reaction.PrayerRequestID == objectID && reaction.Identity == identity {
+			types = append(types, reaction.Type)
+		}
+	}
+
+	return types, nil
+}
+
+// GetPrayerIdentities returns the identities that prayed for a request
+func (r *memoryRepository) GetPrayerIdentities(ctx context.Context, prayerID string) ([]string, error) {
+	objectID, err := bson.ObjectIDFromHex(prayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var identities []string
+	for _, reaction := range r.reactions {
+		if reaction.PrayerRequestID == objectID && reaction.Type == data.ReactionPray {
+			identities = append(identities, reaction.Identity)
+		}
+	}
+
+	return identities, nil
+}
+
+// SavePrayerRequest adds a prayer request to a user's saved list. It
+// reports false, without saving anything, if the user had already saved it.
+func (r *memoryRepository) SavePrayerRequest(ctx context.Context, save *data.SavedPrayer) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.savedIndexOf(save.PrayerRequestID, save.UserID) >= 0 {
+		return false, nil
+	}
+
+	stored := *save
+	if stored.ID.IsZero() {
+		stored.ID = bson.NewObjectID()
+	}
+
+	r.saved = append(r.saved, &stored)
+	return true, nil
+}
+
+// UnsavePrayerRequest removes a prayer request from a user's saved list. It
+// reports whether the request had been saved.
+func (r *memoryRepository) UnsavePrayerRequest(ctx context.Context, prayerID string, userID bson.ObjectID) (bool, error) {
+	objectID, err := bson.ObjectIDFromHex(prayerID)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.savedIndexOf(objectID, userID)
+	if i < 0 {
+		return false, nil
+	}
+
+	r.saved = slices.Delete(r.saved, i, i+1)
+	return true, nil
+}
+
+// IncrementSavedCount adds delta to the saved count of a prayer request
+func (r *memoryRepository) IncrementSavedCount(ctx context.Context, id string, delta int) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i := r.indexOf(objectID); i >= 0 && r.requests[i].DeletedAt == nil {
+		r.requests[i].SavedCount += delta
+	}
+	return nil
+}
+
+// GetSavedPrayerRequests retrieves a page of a user's saved prayer requests,
+// skipping requests in the trash or hidden by moderation
+func (r *memoryRepository) GetSavedPrayerRequests(ctx context.Context, userID bson.ObjectID, page pagination.Params) (*pagination.Page[*data.SavedPrayerRequest], error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var saved []*data.SavedPrayerRequest
+	for _, save := range r.saved {
+		if save.UserID != userID {
+			continue
+		}
+		i := r.indexOf(save.PrayerRequestID)
+		if i < 0 || r.requests[i].DeletedAt != nil || !isPublished(r.requests[i].Moderation) {
+			continue
+		}
+		saved = append(saved, &data.SavedPrayerRequest{
+			PrayerRequest: *copyPrayerRequest(r.requests[i]),
+			SavedAt:       save.CreatedAt,
+			SaveID:        save.ID,
+		})
+	}
+
+	return pagination.Slice(saved, page, savedOrder.Desc, savedPrayerRequestCursor), nil
+}
+
+// SearchPrayerRequests runs a text search over title, description and tags,
+// most relevant first
+func (r *memoryRepository) SearchPrayerRequests(ctx context.Context, query data.SearchQuery, page pagination.Params) (*pagination.Page[*data.SearchResult], error) {
+	text := parseTextQuery(query.Text)
+
+	var results []*data.SearchResult
+	for _, req := range r.findPrayerRequests(func(req *data.PrayerRequest) bool {
+		return (query.IncludeArchived || req.ArchivedAt == nil) &&
+			(query.Category == "" || req.Category == query.Category) &&
+			(query.Priority == "" || req.Priority == query.Priority) &&
+			(query.IsAnswered == nil || req.IsAnswered == *query.IsAnswered)
+	}) {
+		if score := text.score(req); score > 0 {
+			results = append(results, &data.SearchResult{PrayerRequest: *req, Score: score})
+		}
+	}
+
+	return pagination.Slice(results, page, prayerRequestsDesc, searchResultCursor), nil
+}
+
+// GetPrayerRequestsByCategory gets prayer requests by category
+func (r *memoryRepository) GetPrayerRequestsByCategory(ctx context.Context, category string, page pagination.Params) (*pagination.Page[*data.PrayerRequest], error) {
+	requests := r.findPrayerRequests(func(req *data.PrayerRequest) bool {
+		return req.ArchivedAt == nil && req.Category == category
+	})
+	return pagination.Slice(requests, page, prayerRequestsDesc, prayerRequestCursor), nil
+}
+
+// GetRecentPrayerRequests gets recent prayer requests
+func (r *memoryRepository) GetRecentPrayerRequests(ctx context.Context, limit int) ([]*data.PrayerRequest, error) {
+	requests := r.findPrayerRequests(func(req *data.PrayerRequest) bool {
+		return req.ArchivedAt == nil
+	})
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[i].CreatedAt.After(requests[j].CreatedAt)
+	})
+
+	// Mongo treats a limit of zero as "no limit" and a negative limit as
+	// its absolute value
+	if limit < 0 {
+		limit = -limit
+	}
+	if limit > 0 && len(requests) > limit {
+		requests = requests[:limit]
+	}
+
+	return requests, nil
+}
+
+// GetPrayerStats gets prayer statistics
+func (r *memoryRepository) GetPrayerStats(ctx context.Context) (*data.PrayerStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stats := &data.PrayerStats{
+		CategoriesCount: make(map[string]int),
+		LocationsCount:  make(map[string]int),
+		RecentActivity:  []data.ActivityItem{},
+	}
+
+	for _, req := range r.requests {
+		if req.DeletedAt != nil || !isPublished(req.Moderation) {
+			continue
+		}
+		stats.TotalPrayers++
+		stats.TotalPrayCount += req.PrayCount
+		stats.TotalComments += req.CommentCount
+		if req.IsAnswered {
+			stats.AnsweredPrayers++
+		}
+		if req.Priority == "urgent" {
+			stats.UrgentPrayers++
+		}
+		stats.CategoriesCount[req.Category]++
+		if loc := req.Location; loc != nil {
+			if loc.HolySite != "" {
+				stats.LocationsCount[loc.HolySite]++
+			} else {
+				stats.LocationsCount[strings.ToLower(loc.Place)]++
+			}
+		}
+	}
+
+	activity := pagination.Slice(r.findActivities(nil), pagination.Params{Limit: recentActivityLimit}, activitiesDesc, activityCursor)
+	for _, item := range activity.Items {
+		stats.RecentActivity = append(stats.RecentActivity, *item)
+	}
+
+	return stats, nil
+}
+
+// CreateComment creates a new comment
+func (r *memoryRepository) CreateComment(ctx context.Context, comment *data.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := copyComment(comment)
+	if stored.ID.IsZero() {
+		stored.ID = bson.NewObjectID()
+	}
+	for _, c := range r.comments {
+		if c.ID == stored.ID {
+			return fmt.Errorf("duplicate key: comment %s already exists", stored.ID.Hex())
+		}
+	}
+
+	r.comments = append(r.comments, stored)
+	r.incrementCommentCount(stored.PrayerRequestID, commentWeight(stored))
+	return nil
+}
+
+// incrementCommentCount adds delta to the comment count of a prayer
+// request, in the trash or not. Callers must hold the lock.
+func (r *memoryRepository) incrementCommentCount(prayerID bson.ObjectID, delta int) {
+	if i := r.indexOf(prayerID); i >= 0 {
+		r.requests[i].CommentCount += delta
+	}
+}
+
+// RepairCommentCounts recomputes the comment count of every prayer request
+// from the comments and returns how many were wrong
+func (r *memoryRepository) RepairCommentCounts(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := make(map[bson.ObjectID]int)
+	for _, c := range r.comments {
+		counts[c.PrayerRequestID] += commentWeight(c)
+	}
+
+	repaired := 0
+	for _, req := range r.requests {
+		if req.CommentCount != counts[req.ID] {
+			req.CommentCount = counts[req.ID]
+			repaired++
+		}
+	}
+
+	return repaired, nil
+}
+
+// GetCommentsByPrayerID gets a page of comments for a prayer request
+func (r *memoryRepository) GetCommentsByPrayerID(ctx context.Context, prayerID string, page pagination.Params) (*pagination.Page[*data.Comment], error) {
+	objectID, err := bson.ObjectIDFromHex(prayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var comments []*data.Comment
+	for _, c := range r.comments {
+		if c.PrayerRequestID == objectID && isPublished(c.Moderation) {
+			comments = append(comments, copyComment(c))
+		}
+	}
+
+	return pagination.Slice(comments, page, commentsDesc, commentCursor), nil
+}
+
+// GetCommentThreads gets a page of the top-level comments on a prayer request
+func (r *memoryRepository) GetCommentThreads(ctx context.Context, prayerID string, page pagination.Params) (*pagination.Page[*data.Comment], error) {
+	objectID, err := bson.ObjectIDFromHex(prayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var comments []*data.Comment
+	for _, c := range r.comments {
+		if c.PrayerRequestID == objectID && c.ThreadID.IsZero() && isPublished(c.Moderation) {
+			comments = append(comments, copyComment(c))
+		}
+	}
+
+	return pagination.Slice(comments, page, commentsDesc, commentCursor), nil
+}
+
+// GetCommentReplies gets every reply in the given threads, oldest first
+func (r *memoryRepository) GetCommentReplies(ctx context.Context, threadIDs []bson.ObjectID) ([]*data.Comment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var replies []*data.Comment
+	for _, c := range r.comments {
+		if !c.ThreadID.IsZero() && slices.Contains(threadIDs, c.ThreadID) && isPublished(c.Moderation) {
+			replies = append(replies, copyComment(c))
+		}
+	}
+	sort.SliceStable(replies, func(i, j int) bool {
+		return pagination.Less(commentCursor(replies[i]), commentCursor(replies[j]))
+	})
+
+	return replies, nil
+}
+
+// UpdateComment updates a comment. Tombstones of deleted comments are left alone.
+func (r *memoryRepository) UpdateComment(ctx context.Context, id string, comment *data.Comment) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i := r.commentIndexOf(objectID); i >= 0 && r.comments[i].DeletedAt == nil {
+		before := r.comments[i]
+		stored := copyComment(comment)
+		stored.ID = objectID
+		r.comments[i] = stored
+		r.incrementCommentCount(before.PrayerRequestID, commentWeight(stored)-commentWeight(before))
+	}
+	return nil
+}
+
+// CreateActivity appends an item to the activity log
+func (r *memoryRepository) CreateActivity(ctx context.Context, item *data.ActivityItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *item
+	if stored.ID.IsZero() {
+		stored.ID = bson.NewObjectID()
+	}
+
+	r.activities = append(r.activities, &stored)
+	return nil
+}
+
+// GetActivities gets a page of the activity log, optionally limited to some types
+func (r *memoryRepository) GetActivities(ctx context.Context, types []string, page pagination.Params) (*pagination.Page[*data.ActivityItem], error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return pagination.Slice(r.findActivities(types), page, activitiesDesc, activityCursor), nil
+}
+
+// findActivities returns copies of the activity items of the given types,
+// or of every type when types is empty, leaving out trashed activity.
+// Callers must hold the lock.
+func (r *memoryRepository) findActivities(types []string) []*data.ActivityItem {
+	var items []*data.ActivityItem
+	for _, item := range r.activities {
+		if item.DeletedAt == nil && (len(types) == 0 || slices.Contains(types, item.Type)) {
+			activity := *item
+			items = append(items, &activity)
+		}
+	}
+
+	return items
+}
+
+// findPrayerRequests returns copies of the published prayer requests that
+// are not in the trash and match the predicate, in insertion order
+func (r *memoryRepository) findPrayerRequests(match func(*data.PrayerRequest) bool) []*data.PrayerRequest {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var requests []*data.PrayerRequest
+	for _, req := range r.requests {
+		if req.DeletedAt == nil && isPublished(req.Moderation) && match(req) {
+			requests = append(requests, copyPrayerRequest(req))
+		}
+	}
+
+	return requests
+}
+
+// TrashPrayerRequest moves a prayer request and its activity to the trash
+func (r *memoryRepository) TrashPrayerRequest(ctx context.Context, id string, deletedAt time.Time) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(objectID)
+	if i < 0 || r.requests[i].DeletedAt != nil {
+		return nil
+	}
+
+	r.requests[i].DeletedAt = &deletedAt
+	for _, item := range r.activities {
+		if item.PrayerRequestID == objectID {
+			item.DeletedAt = &deletedAt
+		}
+	}
+	return nil
+}
+
+// RestorePrayerRequest takes a prayer request and its activity out of the trash
+func (r *memoryRepository) RestorePrayerRequest(ctx context.Context, id string) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(objectID)
+	if i < 0 || r.requests[i].DeletedAt == nil {
+		return nil
+	}
+
+	r.requests[i].DeletedAt = nil
+	for _, item := range r.activities {
+		if item.PrayerRequestID == objectID {
+			item.DeletedAt = nil
+		}
+	}
+	return nil
+}
+
+// GetTrashedPrayerRequestByID retrieves a prayer request that is in the trash
+func (r *memoryRepository) GetTrashedPrayerRequestByID(ctx context.Context, id string) (*data.PrayerRequest, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.indexOf(objectID)
+	if i < 0 || r.requests[i].DeletedAt == nil {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	return copyPrayerRequest(r.requests[i]), nil
+}
+
+// GetTrashedPrayerRequests retrieves a page of the trash, optionally limited
+// to one user's requests
+func (r *memoryRepository) GetTrashedPrayerRequests(ctx context.Context, userID *bson.ObjectID, page pagination.Params) (*pagination.Page[*data.PrayerRequest], error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var requests []*data.PrayerRequest
+	for _, req := range r.requests {
+		if req.DeletedAt != nil && (userID == nil || req.UserID == *userID) {
+			requests = append(requests, copyPrayerRequest(req))
+		}
+	}
+
+	return pagination.Slice(requests, page, trashOrder.Desc, trashedPrayerRequestCursor), nil
+}
+
+// PurgePrayerRequests permanently deletes the prayer requests trashed
+// before the given time, along with their comments, reactions, saves,
+// activity and reports, and whatever purgeOthers deletes for them
+func (r *memoryRepository) PurgePrayerRequests(ctx context.Context, deletedBefore time.Time, purgeOthers PurgeFunc) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []bson.ObjectID
+	for _, req := range r.requests {
+		if req.DeletedAt != nil && req.DeletedAt.Before(deletedBefore) {
+			ids = append(ids, req.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if err := purgeOthers(ctx, ids); err != nil {
+		return 0, err
+	}
+
+	purged := make(map[bson.ObjectID]bool)
+	for _, id := range ids {
+		purged[id] = true
+	}
+	r.requests = slices.DeleteFunc(r.requests, func(req *data.PrayerRequest) bool {
+		return purged[req.ID]
+	})
+	r.comments = slices.DeleteFunc(r.comments, func(c *data.Comment) bool {
+		return purged[c.PrayerRequestID]
+	})
+	r.reactions = slices.DeleteFunc(r.reactions, func(reaction *data.Reaction) bool {
+		return purged[reaction.PrayerRequestID]
+	})
+	r.saved = slices.DeleteFunc(r.saved, func(save *data.SavedPrayer) bool {
+		return purged[save.PrayerRequestID]
+	})
+	r.activities = slices.DeleteFunc(r.activities, func(item *data.ActivityItem) bool {
+		return purged[item.PrayerRequestID]
+	})
+	r.reports = slices.DeleteFunc(r.reports, func(report *data.Report) bool {
+		return purged[report.PrayerRequestID]
+	})
+
+	return len(purged), nil
+}
+
+// ArchiveExpiredPrayerRequests archives the prayer requests whose expiry
+// has passed and reports how many
+func (r *memoryRepository) ArchiveExpiredPrayerRequests(ctx context.Context, now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	archived := 0
+	for _, req := range r.requests {
+		if req.DeletedAt == nil && req.ArchivedAt == nil && req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+			archivedAt := now
+			req.ArchivedAt = &archivedAt
+			archived++
+		}
+	}
+
+	return archived, nil
+}
+
+// GetArchivedPrayerRequests retrieves a page of a user's archived prayer
+// requests, leaving out the anonymous ones unless includeAnonymous is set
+func (r *memoryRepository) GetArchivedPrayerRequests(ctx context.Context, userID bson.ObjectID, includeAnonymous bool, page pagination.Params) (*pagination.Page[*data.PrayerRequest], error) {
+	requests := r.findPrayerRequests(func(req *data.PrayerRequest) bool {
+		return req.UserID == userID && req.ArchivedAt != nil && (includeAnonymous || !req.IsAnonymous)
+	})
+
+	return pagination.Slice(requests, page, archiveOrder.Desc, archivedPrayerRequestCursor), nil
+}
+
+// GetAnsweredPrayerRequests retrieves a page of answered prayer requests,
+// optionally limited to one category
+func (r *memoryRepository) GetAnsweredPrayerRequests(ctx context.Context, category string, page pagination.Params) (*pagination.Page[*data.PrayerRequest], error) {
+	requests := r.findPrayerRequests(func(req *data.PrayerRequest) bool {
+		return req.AnsweredAt != nil && req.ArchivedAt == nil && (category == "" || req.Category == category)
+	})
+
+	return pagination.Slice(requests, page, answeredOrder.Desc, answeredPrayerRequestCursor), nil
+}
+
+// GetCommentByID retrieves a comment by ID, whatever its moderation status
+func (r *memoryRepository) GetCommentByID(ctx context.Context, id string) (*data.Comment, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.commentIndexOf(objectID)
+	if i < 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	return copyComment(r.comments[i]), nil
+}
+
+// SetModerationStatus moves a prayer request or comment to a moderation
+// status. Publishing clears the report count, so it takes fresh reports to
+// hold the content again, and records when it was first published.
+func (r *memoryRepository) SetModerationStatus(ctx context.Context, target, id, status string, reasons []string, now time.Time) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m := r.moderated(target, objectID)
+	if m == nil {
+		return nil
+	}
+
+	// A comment going in or out of view changes its request's comment count
+	if target == data.ReportTargetComment {
+		comment := r.comments[r.commentIndexOf(objectID)]
+		after := *comment
+		after.ModerationStatus = status
+		r.incrementCommentCount(comment.PrayerRequestID, commentWeight(&after)-commentWeight(comment))
+	}
+
+	m.ModerationStatus = status
+	m.ModerationReasons = slices.Clone(reasons)
+	if status == moderation.StatusPublished {
+		m.ReportCount = 0
+		if m.PublishedAt == nil {
+			publishedAt := now
+			m.PublishedAt = &publishedAt
+		}
+	}
+	return nil
+}
+
+// CreateReport records a report. It reports false, without recording
+// anything, if the identity had already reported the target.
+func (r *memoryRepository) CreateReport(ctx context.Context, report *data.Report) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if slices.ContainsFunc(r.reports, func(existing *data.Report) bool {
+		return existing.TargetType == report.TargetType &&
+			existing.TargetID == report.TargetID &&
+			existing.Identity == report.Identity
+	}) {
+		return false, nil
+	}
+
+	stored := *report
+	if stored.ID.IsZero() {
+		stored.ID = bson.NewObjectID()
+	}
+
+	r.reports = append(r.reports, &stored)
+	return true, nil
+}
+
+// IncrementReportCount increments the report count of a prayer request or
+// comment and returns the new count
+func (r *memoryRepository) IncrementReportCount(ctx context.Context, target, id string) (int, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m := r.moderated(target, objectID)
+	if m == nil {
+		return 0, mongo.ErrNoDocuments
+	}
+
+	m.ReportCount++
+	return m.ReportCount, nil
+}
+
+// GetReports retrieves a page of the reports on a prayer request or comment
+func (r *memoryRepository) GetReports(ctx context.Context, target, id string, page pagination.Params) (*pagination.Page[*data.Report], error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var reports []*data.Report
+	for _, report := range r.reports {
+		if report.TargetType == target && report.TargetID == objectID {
+			stored := *report
+			reports = append(reports, &stored)
+		}
+	}
+
+	return pagination.Slice(reports, page, reportsDesc, reportCursor), nil
+}
+
+// GetPrayerRequestsByStatus retrieves a page of the prayer requests in a
+// moderation status, oldest first
+func (r *memoryRepository) GetPrayerRequestsByStatus(ctx context.Context, status string, page pagination.Params) (*pagination.Page[*data.PrayerRequest], error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var requests []*data.PrayerRequest
+	for _, req := range r.requests {
+		if req.DeletedAt == nil && req.ModerationStatus == status {
+			requests = append(requests, copyPrayerRequest(req))
+		}
+	}
+
+	return pagination.Slice(requests, page, queueDesc, prayerRequestCursor), nil
+}
+
+// GetCommentsByStatus retrieves a page of the comments in a moderation
+// status, oldest first
+func (r *memoryRepository) GetCommentsByStatus(ctx context.Context, status string, page pagination.Params) (*pagination.Page[*data.Comment], error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var comments []*data.Comment
+	for _, c := range r.comments {
+		if c.ModerationStatus == status {
+			comments = append(comments, copyComment(c))
+		}
+	}
+
+	return pagination.Slice(comments, page, queueDesc, commentCursor), nil
+}
+
+// moderated returns the moderation state of a prayer request or comment,
+// or nil if there is none. Prayer requests in the trash are left alone.
+// Callers must hold the lock.
+func (r *memoryRepository) moderated(target string, id bson.ObjectID) *data.Moderation {
+	if target == data.ReportTargetComment {
+		if i := r.commentIndexOf(id); i >= 0 {
+			return &r.comments[i].Moderation
+		}
+		return nil
+	}
+	if i := r.indexOf(id); i >= 0 && r.requests[i].DeletedAt == nil {
+		return &r.requests[i].Moderation
+	}
+	return nil
+}
+
+// isPublished reports whether content passed moderation
+func isPublished(m data.Moderation) bool {
+	return m.ModerationStatus == moderation.StatusPublished
+}
+
+// matchesPrayerFilter reports whether a prayer request passes the filter
+// the same way prayerFilterQuery matches it in Mongo
+func matchesPrayerFilter(req *data.PrayerRequest, filter data.PrayerFilter) bool {
+	switch {
+	case filter.Category != "" && req.Category != filter.Category,
+		filter.Priority != "" && req.Priority != filter.Priority,
+		filter.IsAnswered != nil && req.IsAnswered != *filter.IsAnswered,
+		filter.UserID != nil && req.UserID != *filter.UserID,
+		filter.ExcludeAnonymous && req.IsAnonymous,
+		filter.CreatedAfter != nil && req.CreatedAt.Before(*filter.CreatedAfter),
+		filter.CreatedBefore != nil && !req.CreatedAt.Before(*filter.CreatedBefore),
+		req.PrayCount < filter.MinPrayCount,
+		!filter.IncludeArchived && req.ArchivedAt != nil:
+		return false
+	}
+	if filter.HolySite != "" && (req.Location == nil || req.Location.HolySite != filter.HolySite) {
+		return false
+	}
+	if filter.Place != "" && (req.Location == nil || !strings.EqualFold(req.Location.Place, filter.Place)) {
+		return false
+	}
+	for _, tag := range filter.Tags {
+		if !slices.Contains(req.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// reactionIndexOf returns the position of an identity's reaction of a type
+// to a prayer request, or -1. Callers must hold the lock.
+func (r *memoryRepository) reactionIndexOf(prayerID bson.ObjectID, reactionType, identity string) int {
+	return slices.IndexFunc(r.reactions, func(reaction *data.Reaction) bool {
+		return reaction.PrayerRequestID == prayerID && reaction.Type == reactionType && reaction.Identity == identity
+	})
+}
+
+// savedIndexOf returns the position of a user's save of a prayer request, or -1.
+// Callers must hold the lock.
+func (r *memoryRepository) savedIndexOf(prayerID, userID bson.ObjectID) int {
+	return slices.IndexFunc(r.saved, func(save *data.SavedPrayer) bool {
+		return save.PrayerRequestID == prayerID && save.UserID == userID
+	})
+}
+
+// commentIndexOf returns the position of the comment with the given ID, or -1.
+// Callers must hold the lock.
+func (r *memoryRepository) commentIndexOf(id bson.ObjectID) int {
+	return slices.IndexFunc(r.comments, func(c *data.Comment) bool {
+		return c.ID == id
+	})
+}
+
+// indexOf returns the position of the prayer request with the given ID, or -1.
+// Callers must hold the lock.
+func (r *memoryRepository) indexOf(id bson.ObjectID) int {
+	return slices.IndexFunc(r.requests, func(req *data.PrayerRequest) bool {
+		return req.ID == id
+	})
+}
+
+// copyPrayerRequest returns a deep copy so callers can't mutate stored state
+func copyPrayerRequest(req *data.PrayerRequest) *data.PrayerRequest {
+	c := *req
+	c.Tags = slices.Clone(req.Tags)
+	c.ReactionCounts = maps.Clone(req.ReactionCounts)
+	if req.Location != nil {
+		location := *req.Location
+		if location.Coordinates != nil {
+			coordinates := *location.Coordinates
+			location.Coordinates = &coordinates
+		}
+		c.Location = &location
+	}
+	for _, t := range []**time.Time{&c.AnsweredAt, &c.ExpiresAt, &c.ArchivedAt, &c.DeletedAt} {
+		if *t != nil {
+			value := **t
+			*t = &value
+		}
+	}
+	c.Moderation = copyModeration(req.Moderation)
+	return &c
+}
+
+// copyComment returns a deep copy so callers can't mutate stored state
+func copyComment(comment *data.Comment) *data.Comment {
+	c := *comment
+	c.Replies = nil // never stored
+	c.Moderation = copyModeration(comment.Moderation)
+	return &c
+}
+
+// copyModeration returns a deep copy of a moderation state
+func copyModeration(m data.Moderation) data.Moderation {
+	m.ModerationReasons = slices.Clone(m.ModerationReasons)
+	if m.PublishedAt != nil {
+		publishedAt := *m.PublishedAt
+		m.PublishedAt = &publishedAt
+	}
+	return m
+}
@@ -0,0 +1,350 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"prayerreq-backend/internal/controller/prayer/data"
+	"prayerreq-backend/internal/moderation"
+	"prayerreq-backend/internal/pagination"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var publishedState = data.Moderation{ModerationStatus: moderation.StatusPublished}
+
+// seedPrayerRequests stores published prayer requests created a minute
+// apart, oldest first, with the given pray counts
+func seedPrayerRequests(t *testing.T, repo Repository, prayCounts ...int) []*data.PrayerRequest {
+	t.Helper()
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var requests []*data.PrayerRequest
+	for i, count := range prayCounts {
+		req := &data.PrayerRequest{
+			ID:         bson.NewObjectID(),
+			Title:      "Request",
+			PrayCount:  count,
+			CreatedAt:  start.Add(time.Duration(i) * time.Minute),
+			UpdatedAt:  start.Add(time.Duration(i) * time.Minute),
+			Moderation: publishedState,
+		}
+		if err := repo.CreatePrayerRequest(context.Background(), req); err != nil {
+			t.Fatalf("CreatePrayerRequest: %v", err)
+		}
+		requests = append(requests, req)
+	}
+	return requests
+}
+
+func ids(requests []*data.PrayerRequest) []bson.ObjectID {
+	var result []bson.ObjectID
+	for _, req := range requests {
+		result = append(result, req.ID)
+	}
+	return result
+}
+
+func assertIDs(t *testing.T, got []*data.PrayerRequest, want ...*data.PrayerRequest) {
+	t.Helper()
+	gotIDs, wantIDs := ids(got), ids(want)
+	if len(gotIDs) != len(wantIDs) {
+		t.Fatalf("got %d requests, want %d", len(gotIDs), len(wantIDs))
+	}
+	for i := range wantIDs {
+		if gotIDs[i] != wantIDs[i] {
+			t.Fatalf("request %d = %s, want %s", i, gotIDs[i].Hex(), wantIDs[i].Hex())
+		}
+	}
+}
+
+func TestMemoryGetPrayerRequestsSortOrder(t *testing.T) {
+	repo := NewMemoryRepository()
+	reqs := seedPrayerRequests(t, repo, 5, 9, 1)
+	page := pagination.Params{Limit: pagination.DefaultLimit}
+
+	tests := []struct {
+		sort string
+		want []*data.PrayerRequest
+	}{
+		{"", []*data.PrayerRequest{reqs[2], reqs[1], reqs[0]}},
+		{data.SortNewest, []*data.PrayerRequest{reqs[2], reqs[1], reqs[0]}},
+		{data.SortOldest, []*data.PrayerRequest{reqs[0], reqs[1], reqs[2]}},
+		{data.SortMostPrayed, []*data.PrayerRequest{reqs[1], reqs[0], reqs[2]}},
+		{data.SortLeastPrayed, []*data.PrayerRequest{reqs[2], reqs[0], reqs[1]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			got, err := repo.GetPrayerRequests(context.Background(), data.PrayerFilter{Sort: tt.sort}, page)
+			if err != nil {
+				t.Fatalf("GetPrayerRequests: %v", err)
+			}
+			assertIDs(t, got.Items, tt.want...)
+		})
+	}
+}
+
+func TestMemoryGetPrayerRequestsPages(t *testing.T) {
+	repo := NewMemoryRepository()
+	reqs := seedPrayerRequests(t, repo, 0, 0, 0)
+	ctx := context.Background()
+
+	first, err := repo.GetPrayerRequests(ctx, data.PrayerFilter{}, pagination.Params{Limit: 2})
+	if err != nil {
+		t.Fatalf("GetPrayerRequests: %v", err)
+	}
+	assertIDs(t, first.Items, reqs[2], reqs[1])
+	if !first.HasMore || first.NextCursor == "" {
+		t.Fatalf("first page has_more = %v, next_cursor = %q; want more", first.HasMore, first.NextCursor)
+	}
+
+	cursor, err := pagination.Decode(first.NextCursor)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	second, err := repo.GetPrayerRequests(ctx, data.PrayerFilter{}, pagination.Params{Limit: 2, Cursor: cursor})
+	if err != nil {
+		t.Fatalf("GetPrayerRequests: %v", err)
+	}
+	assertIDs(t, second.Items, reqs[0])
+	if second.HasMore {
+		t.Error("second page has_more = true, want false")
+	}
+}
+
+func TestMemoryGetPrayerRequestsLeavesOutHiddenRequests(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	reqs := seedPrayerRequests(t, repo, 0, 0, 0)
+
+	if err := repo.SetModerationStatus(ctx, data.ReportTargetPrayer, reqs[0].ID.Hex(),
+		moderation.StatusPendingReview, nil, time.Now()); err != nil {
+		t.Fatalf("SetModerationStatus: %v", err)
+	}
+	if err := repo.TrashPrayerRequest(ctx, reqs[1].ID.Hex(), time.Now()); err != nil {
+		t.Fatalf("TrashPrayerRequest: %v", err)
+	}
+
+	got, err := repo.GetPrayerRequests(ctx, data.PrayerFilter{}, pagination.Params{Limit: pagination.DefaultLimit})
+	if err != nil {
+		t.Fatalf("GetPrayerRequests: %v", err)
+	}
+	assertIDs(t, got.Items, reqs[2])
+}
+
+func TestMemoryUserIDFilterExcludesAnonymous(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	userID := bson.NewObjectID()
+
+	named := &data.PrayerRequest{UserID: userID, CreatedAt: time.Now(), Moderation: publishedState}
+	anonymous := &data.PrayerRequest{UserID: userID, IsAnonymous: true, CreatedAt: time.Now().Add(time.Second), Moderation: publishedState}
+	for _, req := range []*data.PrayerRequest{named, anonymous} {
+		req.ID = bson.NewObjectID()
+		if err := repo.CreatePrayerRequest(ctx, req); err != nil {
+			t.Fatalf("CreatePrayerRequest: %v", err)
+		}
+	}
+
+	page := pagination.Params{Limit: pagination.DefaultLimit}
+	got, err := repo.GetPrayerRequests(ctx, data.PrayerFilter{UserID: &userID}, page)
+	if err != nil {
+		t.Fatalf("GetPrayerRequests: %v", err)
+	}
+	assertIDs(t, got.Items, anonymous, named)
+
+	got, err = repo.GetPrayerRequests(ctx, data.PrayerFilter{UserID: &userID, ExcludeAnonymous: true}, page)
+	if err != nil {
+		t.Fatalf("GetPrayerRequests: %v", err)
+	}
+	assertIDs(t, got.Items, named)
+}
+
+func TestMemoryGetPrayerRequestByIDErrors(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	reqs := seedPrayerRequests(t, repo, 0)
+
+	if _, err := repo.GetPrayerRequestByID(ctx, bson.NewObjectID().Hex()); !errors.Is(err, mongo.ErrNoDocuments) {
+		t.Errorf("missing ID: err = %v, want mongo.ErrNoDocuments", err)
+	}
+	if _, err := repo.GetPrayerRequestByID(ctx, "not-an-id"); !errors.Is(err, bson.ErrInvalidHex) {
+		t.Errorf("invalid ID: err = %v, want bson.ErrInvalidHex", err)
+	}
+
+	if err := repo.TrashPrayerRequest(ctx, reqs[0].ID.Hex(), time.Now()); err != nil {
+		t.Fatalf("TrashPrayerRequest: %v", err)
+	}
+	if _, err := repo.GetPrayerRequestByID(ctx, reqs[0].ID.Hex()); !errors.Is(err, mongo.ErrNoDocuments) {
+		t.Errorf("trashed ID: err = %v, want mongo.ErrNoDocuments", err)
+	}
+}
+
+func TestMemoryGetPrayerRequestByIDReturnsCopy(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	reqs := seedPrayerRequests(t, repo, 0)
+
+	got, err := repo.GetPrayerRequestByID(ctx, reqs[0].ID.Hex())
+	if err != nil {
+		t.Fatalf("GetPrayerRequestByID: %v", err)
+	}
+	got.Title = "Changed"
+
+	again, err := repo.GetPrayerRequestByID(ctx, reqs[0].ID.Hex())
+	if err != nil {
+		t.Fatalf("GetPrayerRequestByID: %v", err)
+	}
+	if again.Title != "Request" {
+		t.Errorf("stored title = %q, want it unchanged by the caller", again.Title)
+	}
+}
+
+func TestMemoryReactionsAreCountedOnce(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	req := seedPrayerRequests(t, repo, 0)[0]
+	id := req.ID.Hex()
+
+	for i, want := range []bool{true, false} {
+		added, err := repo.AddReaction(ctx, &data.Reaction{PrayerRequestID: req.ID, Type: data.ReactionPray, Identity: "device:abc"})
+		if err != nil {
+			t.Fatalf("AddReaction: %v", err)
+		}
+		if added != want {
+			t.Errorf("AddReaction #%d = %v, want %v", i+1, added, want)
+		}
+	}
+	if err := repo.IncrementReactionCount(ctx, id, data.ReactionPray, 1); err != nil {
+		t.Fatalf("IncrementReactionCount: %v", err)
+	}
+
+	got, err := repo.GetPrayerRequestByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetPrayerRequestByID: %v", err)
+	}
+	if got.PrayCount != 1 || got.ReactionCounts[data.ReactionPray] != 1 {
+		t.Errorf("pray_count = %d, reaction_counts = %v; want 1 pray", got.PrayCount, got.ReactionCounts)
+	}
+
+	if _, err := repo.RemoveReaction(ctx, "not-an-id", data.ReactionPray, "device:abc"); !errors.Is(err, bson.ErrInvalidHex) {
+		t.Errorf("RemoveReaction with invalid ID: err = %v, want bson.ErrInvalidHex", err)
+	}
+}
+
+func TestMemoryCommentsOldestFirst(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	req := seedPrayerRequests(t, repo, 0)[0]
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var want []bson.ObjectID
+	for i := range 3 {
+		comment := &data.Comment{
+			ID:              bson.NewObjectID(),
+			PrayerRequestID: req.ID,
+			Message:         "Ameen",
+			CreatedAt:       start.Add(time.Duration(i) * time.Minute),
+			Moderation:      publishedState,
+		}
+		if err := repo.CreateComment(ctx, comment); err != nil {
+			t.Fatalf("CreateComment: %v", err)
+		}
+		want = append(want, comment.ID)
+	}
+
+	got, err := repo.GetCommentsByPrayerID(ctx, req.ID.Hex(), pagination.Params{Limit: pagination.DefaultLimit})
+	if err != nil {
+		t.Fatalf("GetCommentsByPrayerID: %v", err)
+	}
+	if len(got.Items) != len(want) {
+		t.Fatalf("got %d comments, want %d", len(got.Items), len(want))
+	}
+	for i, comment := range got.Items {
+		if comment.ID != want[i] {
+			t.Errorf("comment %d = %s, want %s", i, comment.ID.Hex(), want[i].Hex())
+		}
+	}
+
+	stored, err := repo.GetPrayerRequestByID(ctx, req.ID.Hex())
+	if err != nil {
+		t.Fatalf("GetPrayerRequestByID: %v", err)
+	}
+	if stored.CommentCount != len(want) {
+		t.Errorf("comment_count = %d, want %d", stored.CommentCount, len(want))
+	}
+
+	if _, err := repo.GetCommentByID(ctx, bson.NewObjectID().Hex()); !errors.Is(err, mongo.ErrNoDocuments) {
+		t.Errorf("missing comment: err = %v, want mongo.ErrNoDocuments", err)
+	}
+}
+
+func TestMemoryUpdatePrayerRequestKeepsCounters(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	req := seedPrayerRequests(t, repo, 0)[0]
+	id := req.ID.Hex()
+
+	// A comment and a reaction land after the edit read the request
+	comment := &data.Comment{ID: bson.NewObjectID(), PrayerRequestID: req.ID, Message: "Ameen", Moderation: publishedState}
+	if err := repo.CreateComment(ctx, comment); err != nil {
+		t.Fatalf("CreateComment: %v", err)
+	}
+	if err := repo.IncrementReactionCount(ctx, id, data.ReactionPray, 1); err != nil {
+		t.Fatalf("IncrementReactionCount: %v", err)
+	}
+
+	title := "Edited"
+	got, err := repo.UpdatePrayerRequest(ctx, id, &data.PrayerRequestUpdate{Title: &title, UpdatedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("UpdatePrayerRequest: %v", err)
+	}
+	if got.Title != title || got.CommentCount != 1 || got.PrayCount != 1 {
+		t.Errorf("title = %q, comment_count = %d, pray_count = %d; want the edit and both counts kept",
+			got.Title, got.CommentCount, got.PrayCount)
+	}
+
+	if err := repo.TrashPrayerRequest(ctx, id, time.Now()); err != nil {
+		t.Fatalf("TrashPrayerRequest: %v", err)
+	}
+	if _, err := repo.UpdatePrayerRequest(ctx, id, &data.PrayerRequestUpdate{Title: &title}); !errors.Is(err, mongo.ErrNoDocuments) {
+		t.Errorf("trashed ID: err = %v, want mongo.ErrNoDocuments", err)
+	}
+}
+
+func TestMemoryPurgePrayerRequestsPurgesOthers(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	reqs := seedPrayerRequests(t, repo, 0, 0)
+	trashedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := repo.TrashPrayerRequest(ctx, reqs[0].ID.Hex(), trashedAt); err != nil {
+		t.Fatalf("TrashPrayerRequest: %v", err)
+	}
+
+	// A failure elsewhere keeps the request so the next run tries again
+	failing := func(ctx context.Context, ids []bson.ObjectID) error { return errors.New("unavailable") }
+	if _, err := repo.PurgePrayerRequests(ctx, trashedAt.Add(time.Hour), failing); err == nil {
+		t.Fatal("PurgePrayerRequests with a failing purge of others: err = nil")
+	}
+
+	var others []bson.ObjectID
+	purgeOthers := func(ctx context.Context, ids []bson.ObjectID) error {
+		others = append(others, ids...)
+		return nil
+	}
+	purged, err := repo.PurgePrayerRequests(ctx, trashedAt.Add(time.Hour), purgeOthers)
+	if err != nil {
+		t.Fatalf("PurgePrayerRequests: %v", err)
+	}
+	if purged != 1 || len(others) != 1 || others[0] != reqs[0].ID {
+		t.Errorf("purged %d, others purged for %v; want only the trashed request", purged, others)
+	}
+	if _, err := repo.GetTrashedPrayerRequestByID(ctx, reqs[0].ID.Hex()); !errors.Is(err, mongo.ErrNoDocuments) {
+		t.Errorf("purged request: err = %v, want mongo.ErrNoDocuments", err)
+	}
+}
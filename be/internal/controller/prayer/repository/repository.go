@@ -2,43 +2,238 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"prayerreq-backend/internal/controller/prayer/data"
+	"prayerreq-backend/internal/moderation"
+	"prayerreq-backend/internal/pagination"
+	"regexp"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
-// Repository defines the interface for prayer request data access
+// Repository defines the interface for prayer request data access. Listings
+// only include published requests and comments; content held for review is
+// listed through the moderation methods. The comment methods keep each
+// prayer request's comment_count in step with its counted comments.
 type Repository interface {
 	CreatePrayerRequest(ctx context.Context, req *data.PrayerRequest) error
 	GetPrayerRequestByID(ctx context.Context, id string) (*data.PrayerRequest, error)
-	GetPrayerRequests(ctx context.Context) ([]*data.PrayerRequest, error)
-	UpdatePrayerRequest(ctx context.Context, id string, req *data.PrayerRequest) error
-	DeletePrayerRequest(ctx context.Context, id string) error
-	IncrementPrayCount(ctx context.Context, id string) error
+	GetPrayerRequests(ctx context.Context, filter data.PrayerFilter, page pagination.Params) (*pagination.Page[*data.PrayerRequest], error)
+	UpdatePrayerRequest(ctx context.Context, id string, update *data.PrayerRequestUpdate) (*data.PrayerRequest, error)
+	// Reaction methods
+	AddReaction(ctx context.Context, reaction *data.Reaction) (bool, error)
+	RemoveReaction(ctx context.Context, prayerID, reactionType, identity string) (bool, error)
+	IncrementReactionCount(ctx context.Context, id, reactionType string, delta int) error
+	GetIdentityReactions(ctx context.Context, prayerID, identity string) ([]string, error)
+	GetPrayerIdentities(ctx context.Context, prayerID string) ([]string, error)
+	// Saved prayer methods
+	SavePrayerRequest(ctx context.Context, save *data.SavedPrayer) (bool, error)
+	UnsavePrayerRequest(ctx context.Context, prayerID string, userID bson.ObjectID) (bool, error)
+	IncrementSavedCount(ctx context.Context, id string, delta int) error
+	GetSavedPrayerRequests(ctx context.Context, userID bson.ObjectID, page pagination.Params) (*pagination.Page[*data.SavedPrayerRequest], error)
 	// New methods for enhanced functionality
-	SearchPrayerRequests(ctx context.Context, query string) ([]*data.PrayerRequest, error)
-	GetPrayerRequestsByCategory(ctx context.Context, category string) ([]*data.PrayerRequest, error)
+	SearchPrayerRequests(ctx context.Context, query data.SearchQuery, page pagination.Params) (*pagination.Page[*data.SearchResult], error)
+	GetPrayerRequestsByCategory(ctx context.Context, category string, page pagination.Params) (*pagination.Page[*data.PrayerRequest], error)
 	GetRecentPrayerRequests(ctx context.Context, limit int) ([]*data.PrayerRequest, error)
 	GetPrayerStats(ctx context.Context) (*data.PrayerStats, error)
 	// Comment methods
 	CreateComment(ctx context.Context, comment *data.Comment) error
-	GetCommentsByPrayerID(ctx context.Context, prayerID string) ([]*data.Comment, error)
+	GetCommentsByPrayerID(ctx context.Context, prayerID string, page pagination.Params) (*pagination.Page[*data.Comment], error)
+	GetCommentThreads(ctx context.Context, prayerID string, page pagination.Params) (*pagination.Page[*data.Comment], error)
+	GetCommentReplies(ctx context.Context, threadIDs []bson.ObjectID) ([]*data.Comment, error)
+	UpdateComment(ctx context.Context, id string, comment *data.Comment) error
+	RepairCommentCounts(ctx context.Context) (int, error)
+	// Activity methods
+	CreateActivity(ctx context.Context, item *data.ActivityItem) error
+	GetActivities(ctx context.Context, types []string, page pagination.Params) (*pagination.Page[*data.ActivityItem], error)
+	// Trash methods. Trashed requests are hidden from every other method.
+	TrashPrayerRequest(ctx context.Context, id string, deletedAt time.Time) error
+	RestorePrayerRequest(ctx context.Context, id string) error
+	GetTrashedPrayerRequestByID(ctx context.Context, id string) (*data.PrayerRequest, error)
+	GetTrashedPrayerRequests(ctx context.Context, userID *bson.ObjectID, page pagination.Params) (*pagination.Page[*data.PrayerRequest], error)
+	PurgePrayerRequests(ctx context.Context, deletedBefore time.Time, purgeOthers PurgeFunc) (int, error)
+	// Archive methods. Archived requests are left out of listings by default.
+	ArchiveExpiredPrayerRequests(ctx context.Context, now time.Time) (int, error)
+	GetArchivedPrayerRequests(ctx context.Context, userID bson.ObjectID, includeAnonymous bool, page pagination.Params) (*pagination.Page[*data.PrayerRequest], error)
+	// GetAnsweredPrayerRequests lists answered requests for the testimonies feed
+	GetAnsweredPrayerRequests(ctx context.Context, category string, page pagination.Params) (*pagination.Page[*data.PrayerRequest], error)
+	// Moderation methods. Targets are data.ReportTargetPrayer or data.ReportTargetComment.
+	GetCommentByID(ctx context.Context, id string) (*data.Comment, error)
+	SetModerationStatus(ctx context.Context, target, id, status string, reasons []string, now time.Time) error
+	CreateReport(ctx context.Context, report *data.Report) (bool, error)
+	IncrementReportCount(ctx context.Context, target, id string) (int, error)
+	GetReports(ctx context.Context, target, id string, page pagination.Params) (*pagination.Page[*data.Report], error)
+	GetPrayerRequestsByStatus(ctx context.Context, status string, page pagination.Params) (*pagination.Page[*data.PrayerRequest], error)
+	GetCommentsByStatus(ctx context.Context, status string, page pagination.Params) (*pagination.Page[*data.Comment], error)
+}
+
+// PurgeFunc deletes data kept outside the repository about prayer requests
+// that are being purged
+type PurgeFunc func(ctx context.Context, prayerRequestIDs []bson.ObjectID) error
+
+// recentActivityLimit is the number of activity items included in the stats
+const recentActivityLimit = 10
+
+// Prayer requests, activity and reports are listed newest first, comments
+// and the moderation queue oldest first
+const (
+	prayerRequestsDesc = true
+	commentsDesc       = false
+	activitiesDesc     = true
+	reportsDesc        = true
+	queueDesc          = false
+)
+
+// prayerRequestCursor returns the pagination cursor for a prayer request
+func prayerRequestCursor(req *data.PrayerRequest) pagination.Cursor {
+	return pagination.Cursor{Time: req.CreatedAt, ID: req.ID}
+}
+
+// prayerRequestOrders maps each sort order to its sort keys. Sorting by
+// pray count falls back to creation time among equal counts.
+var prayerRequestOrders = map[string]pagination.Order{
+	data.SortNewest:         pagination.CreatedAt(true),
+	data.SortOldest:         pagination.CreatedAt(false),
+	data.SortMostPrayed:     {ScoreField: "pray_count", TimeField: "created_at", Desc: true},
+	data.SortLeastPrayed:    {ScoreField: "pray_count", TimeField: "created_at", Desc: false},
+	data.SortRecentlyEdited: {TimeField: "updated_at", Desc: true},
+	data.SortLeastEdited:    {TimeField: "updated_at", Desc: false},
+}
+
+// sortedPrayerRequestCursor returns the cursor function for a sort order
+func sortedPrayerRequestCursor(sort string) func(*data.PrayerRequest) pagination.Cursor {
+	return func(req *data.PrayerRequest) pagination.Cursor {
+		c := pagination.Cursor{Sort: sort, Time: req.CreatedAt, ID: req.ID}
+		switch sort {
+		case data.SortMostPrayed, data.SortLeastPrayed:
+			c.Score = float64(req.PrayCount)
+		case data.SortRecentlyEdited, data.SortLeastEdited:
+			c.Time = req.UpdatedAt
+		}
+		return c
+	}
+}
+
+// prayerRequestOrder returns the sort keys for a sort order, newest first by default
+func prayerRequestOrder(sort string) (string, pagination.Order) {
+	if order, ok := prayerRequestOrders[sort]; ok {
+		return sort, order
+	}
+	return data.SortNewest, prayerRequestOrders[data.SortNewest]
+}
+
+// trashOrder lists trashed prayer requests, most recently deleted first
+var trashOrder = pagination.Order{TimeField: "deleted_at", Desc: true}
+
+// trashedPrayerRequestCursor returns the pagination cursor for a trashed prayer request
+func trashedPrayerRequestCursor(req *data.PrayerRequest) pagination.Cursor {
+	return pagination.Cursor{Time: *req.DeletedAt, ID: req.ID}
+}
+
+// archiveOrder lists archived prayer requests, most recently archived first
+var archiveOrder = pagination.Order{TimeField: "archived_at", Desc: true}
+
+// archivedPrayerRequestCursor returns the pagination cursor for an archived prayer request
+func archivedPrayerRequestCursor(req *data.PrayerRequest) pagination.Cursor {
+	return pagination.Cursor{Time: *req.ArchivedAt, ID: req.ID}
+}
+
+// answeredOrder lists answered prayer requests, most recently answered first
+var answeredOrder = pagination.Order{TimeField: "answered_at", Desc: true}
+
+// answeredPrayerRequestCursor returns the pagination cursor for an answered prayer request
+func answeredPrayerRequestCursor(req *data.PrayerRequest) pagination.Cursor {
+	return pagination.Cursor{Time: *req.AnsweredAt, ID: req.ID}
+}
+
+// savedOrder lists a user's saved prayer requests, most recently saved first
+var savedOrder = pagination.CreatedAt(true)
+
+// savedPrayerRequestCursor returns the pagination cursor for a saved prayer
+// request, which follows the save rather than the request
+func savedPrayerRequestCursor(saved *data.SavedPrayerRequest) pagination.Cursor {
+	return pagination.Cursor{Time: saved.SavedAt, ID: saved.SaveID}
+}
+
+// purgeBatchSize is the number of prayer requests purged per round trip
+const purgeBatchSize = 500
+
+// searchOrder ranks search results by score, newest first among equal scores
+var searchOrder = pagination.Order{ScoreField: "score", TimeField: "created_at", Desc: true}
+
+// searchResultCursor returns the pagination cursor for a search result
+func searchResultCursor(result *data.SearchResult) pagination.Cursor {
+	return pagination.Cursor{Score: result.Score, Time: result.CreatedAt, ID: result.ID}
+}
+
+// activityCursor returns the pagination cursor for an activity item
+func activityCursor(item *data.ActivityItem) pagination.Cursor {
+	return pagination.Cursor{Time: item.CreatedAt, ID: item.ID}
+}
+
+// commentCursor returns the pagination cursor for a comment
+func commentCursor(comment *data.Comment) pagination.Cursor {
+	return pagination.Cursor{Time: comment.CreatedAt, ID: comment.ID}
+}
+
+// reportCursor returns the pagination cursor for a report
+func reportCursor(report *data.Report) pagination.Cursor {
+	return pagination.Cursor{Time: report.CreatedAt, ID: report.ID}
 }
 
 // mongoRepository implements Repository interface using MongoDB
 type mongoRepository struct {
 	collection *mongo.Collection
+	comments   *mongo.Collection
+	reactions  *mongo.Collection
+	saved      *mongo.Collection
+	activities *mongo.Collection
+	reports    *mongo.Collection
 }
 
 // NewMongoRepository creates a new MongoDB repository for prayers
 func NewMongoRepository(db *mongo.Database) Repository {
 	return &mongoRepository{
 		collection: db.Collection("prayer_requests"),
+		comments:   db.Collection("comments"),
+		reactions:  db.Collection("reactions"),
+		saved:      db.Collection("saved_prayers"),
+		activities: db.Collection("activities"),
+		reports:    db.Collection("reports"),
 	}
 }
 
+// notDeleted narrows a filter down to documents that are not in the trash.
+// A missing deleted_at matches null.
+func notDeleted(filter bson.M) bson.M {
+	filter["deleted_at"] = nil
+	return filter
+}
+
+// notArchived narrows a filter down to prayer requests that have not expired
+func notArchived(filter bson.M) bson.M {
+	filter["archived_at"] = nil
+	return filter
+}
+
+// published narrows a filter down to content that passed moderation
+func published(filter bson.M) bson.M {
+	filter["moderation_status"] = moderation.StatusPublished
+	return filter
+}
+
+// commentWeight returns how much a comment adds to its prayer request's
+// comment_count: 1 while it is published and not deleted, otherwise 0
+func commentWeight(comment *data.Comment) int {
+	if comment.DeletedAt != nil || comment.ModerationStatus != moderation.StatusPublished {
+		return 0
+	}
+	return 1
+}
+
 // CreatePrayerRequest creates a new prayer request
 func (r *mongoRepository) CreatePrayerRequest(ctx context.Context, req *data.PrayerRequest) error {
 	_, err := r.collection.InsertOne(ctx, req)
@@ -53,7 +248,7 @@ func (r *mongoRepository) GetPrayerRequestByID(ctx context.Context, id string) (
 	}
 
 	var req data.PrayerRequest
-	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&req)
+	err = r.collection.FindOne(ctx, notDeleted(bson.M{"_id": objectID})).Decode(&req)
 	if err != nil {
 		return nil, err
 	}
@@ -61,117 +256,357 @@ func (r *mongoRepository) GetPrayerRequestByID(ctx context.Context, id string) (
 	return &req, nil
 }
 
-// GetPrayerRequests retrieves all prayer requests
-func (r *mongoRepository) GetPrayerRequests(ctx context.Context) ([]*data.PrayerRequest, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+// GetPrayerRequests retrieves a page of prayer requests matching the filter
+func (r *mongoRepository) GetPrayerRequests(ctx context.Context, filter data.PrayerFilter, page pagination.Params) (*pagination.Page[*data.PrayerRequest], error) {
+	sort, order := prayerRequestOrder(filter.Sort)
+	return pagination.FindSortedPage(ctx, r.collection, prayerFilterQuery(filter), page, order, sortedPrayerRequestCursor(sort))
+}
+
+// prayerFilterQuery translates a filter into a Mongo query. Every value is
+// matched literally, so callers can't inject operators.
+func prayerFilterQuery(filter data.PrayerFilter) bson.M {
+	query := published(notDeleted(bson.M{}))
+	if filter.Category != "" {
+		query["category"] = filter.Category
+	}
+	if filter.Priority != "" {
+		query["priority"] = filter.Priority
+	}
+	if len(filter.Tags) > 0 {
+		query["tags"] = bson.M{"$all": filter.Tags}
+	}
+	if filter.IsAnswered != nil {
+		query["is_answered"] = *filter.IsAnswered
+	}
+	if filter.UserID != nil {
+		query["user_id"] = *filter.UserID
+	}
+	if filter.ExcludeAnonymous {
+		query["is_anonymous"] = false
+	}
+	if filter.CreatedAfter != nil || filter.CreatedBefore != nil {
+		createdAt := bson.M{}
+		if filter.CreatedAfter != nil {
+			createdAt["$gte"] = *filter.CreatedAfter
+		}
+		if filter.CreatedBefore != nil {
+			createdAt["$lt"] = *filter.CreatedBefore
+		}
+		query["created_at"] = createdAt
+	}
+	if filter.MinPrayCount > 0 {
+		query["pray_count"] = bson.M{"$gte": filter.MinPrayCount}
+	}
+	if filter.HolySite != "" {
+		query["location.holy_site"] = filter.HolySite
+	}
+	if filter.Place != "" {
+		query["location.place"] = bson.M{"$regex": "^" + regexp.QuoteMeta(filter.Place) + "$", "$options": "i"}
+	}
+	if !filter.IncludeArchived {
+		notArchived(query)
+	}
+
+	return query
+}
+
+// UpdatePrayerRequest sets the fields an edit or answer changes and returns
+// the updated prayer request
+func (r *mongoRepository) UpdatePrayerRequest(ctx context.Context, id string, update *data.PrayerRequestUpdate) (*data.PrayerRequest, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(ctx)
 
-	var requests []*data.PrayerRequest
-	for cursor.Next(ctx) {
-		var req data.PrayerRequest
-		if err := cursor.Decode(&req); err != nil {
-			return nil, err
+	set, unset := bson.M{}, bson.M{}
+	if !update.UpdatedAt.IsZero() {
+		set["updated_at"] = update.UpdatedAt
+	}
+	if update.Title != nil {
+		set["title"] = *update.Title
+	}
+	if update.Description != nil {
+		set["description"] = *update.Description
+	}
+	if update.Priority != nil {
+		set["priority"] = *update.Priority
+	}
+	if update.Category != nil {
+		set["category"] = *update.Category
+	}
+	if update.Tags != nil {
+		set["tags"] = update.Tags
+	}
+	if update.Location != nil {
+		set["location"] = update.Location
+	}
+	if update.ExpiresAt != nil {
+		set["expires_at"] = *update.ExpiresAt
+		unset["archived_at"] = ""
+	}
+	if update.AnsweredAt != nil {
+		set["is_answered"] = true
+		set["answered_at"] = *update.AnsweredAt
+	}
+	if update.Testimony != nil {
+		set["testimony"] = *update.Testimony
+	}
+	if update.AnswerPending != nil {
+		if *update.AnswerPending {
+			set["answer_pending"] = true
+		} else {
+			unset["answer_pending"] = ""
+		}
+	}
+	if update.ModerationStatus != nil {
+		set["moderation_status"] = *update.ModerationStatus
+		if len(update.ModerationReasons) > 0 {
+			set["moderation_reasons"] = update.ModerationReasons
+		} else {
+			unset["moderation_reasons"] = ""
 		}
-		requests = append(requests, &req)
 	}
 
-	return requests, cursor.Err()
+	changes := bson.M{}
+	if len(set) > 0 {
+		changes["$set"] = set
+	}
+	if len(unset) > 0 {
+		changes["$unset"] = unset
+	}
+
+	var req data.PrayerRequest
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = r.collection.FindOneAndUpdate(ctx, notDeleted(bson.M{"_id": objectID}), changes, opts).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &req, nil
 }
 
-// UpdatePrayerRequest updates a prayer request
-func (r *mongoRepository) UpdatePrayerRequest(ctx context.Context, id string, req *data.PrayerRequest) error {
-	objectID, err := bson.ObjectIDFromHex(id)
+// AddReaction records that an identity reacted to a request. It reports
+// false, without recording anything, if the identity had already reacted
+// this way.
+func (r *mongoRepository) AddReaction(ctx context.Context, reaction *data.Reaction) (bool, error) {
+	filter := bson.M{
+		"prayer_request_id": reaction.PrayerRequestID,
+		"type":              reaction.Type,
+		"identity":          reaction.Identity,
+	}
+	update := bson.M{"$setOnInsert": bson.M{
+		"_id":        reaction.ID,
+		"user_id":    reaction.UserID,
+		"user_name":  reaction.UserName,
+		"created_at": reaction.CreatedAt,
+	}}
+
+	result, err := r.reactions.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	_, err = r.collection.ReplaceOne(ctx, bson.M{"_id": objectID}, req)
-	return err
+	return result.UpsertedCount > 0, nil
 }
 
-// DeletePrayerRequest deletes a prayer request
-func (r *mongoRepository) DeletePrayerRequest(ctx context.Context, id string) error {
-	objectID, err := bson.ObjectIDFromHex(id)
+// RemoveReaction removes an identity's reaction of a type from a request.
+// It reports false if there was none.
+func (r *mongoRepository) RemoveReaction(ctx context.Context, prayerID, reactionType, identity string) (bool, error) {
+	objectID, err := bson.ObjectIDFromHex(prayerID)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
-	return err
+	result, err := r.reactions.DeleteOne(ctx, bson.M{
+		"prayer_request_id": objectID,
+		"type":              reactionType,
+		"identity":          identity,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return result.DeletedCount > 0, nil
 }
 
-// IncrementPrayCount increments the pray count for a prayer request
-func (r *mongoRepository) IncrementPrayCount(ctx context.Context, id string) error {
+// IncrementReactionCount adds delta to the count of a reaction type on a
+// prayer request. The pray count moves with the "pray" reactions.
+func (r *mongoRepository) IncrementReactionCount(ctx context.Context, id, reactionType string, delta int) error {
 	objectID, err := bson.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
 
+	inc := bson.M{"reaction_counts." + reactionType: delta}
+	if reactionType == data.ReactionPray {
+		inc["pray_count"] = delta
+	}
+
 	_, err = r.collection.UpdateOne(
 		ctx,
-		bson.M{"_id": objectID},
-		bson.M{"$inc": bson.M{"pray_count": 1}},
+		notDeleted(bson.M{"_id": objectID}),
+		bson.M{"$inc": inc},
 	)
 	return err
 }
 
-// SearchPrayerRequests searches prayer requests by title and description
-func (r *mongoRepository) SearchPrayerRequests(ctx context.Context, query string) ([]*data.PrayerRequest, error) {
-	filter := bson.M{
-		"$or": []bson.M{
-			{"title": bson.M{"$regex": query, "$options": "i"}},
-			{"description": bson.M{"$regex": query, "$options": "i"}},
-			{"tags": bson.M{"$in": []string{query}}},
-		},
+// GetIdentityReactions returns the types of reaction an identity left on a request
+func (r *mongoRepository) GetIdentityReactions(ctx context.Context, prayerID, identity string) ([]string, error) {
+	objectID, err := bson.ObjectIDFromHex(prayerID)
+	if err != nil {
+		return nil, err
 	}
 
-	cursor, err := r.collection.Find(ctx, filter)
+	var types []string
+	err = r.reactions.Distinct(ctx, "type", bson.M{"prayer_request_id": objectID, "identity": identity}).Decode(&types)
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(ctx)
 
-	var requests []*data.PrayerRequest
-	for cursor.Next(ctx) {
-		var req data.PrayerRequest
-		if err := cursor.Decode(&req); err != nil {
-			return nil, err
-		}
-		requests = append(requests, &req)
+	return types, nil
+}
+
+// GetPrayerIdentities returns the identities that prayed for a request
+func (r *mongoRepository) GetPrayerIdentities(ctx context.Context, prayerID string) ([]string, error) {
+	objectID, err := bson.ObjectIDFromHex(prayerID)
+	if err != nil {
+		return nil, err
 	}
 
-	return requests, cursor.Err()
+	var identities []string
+	err = r.reactions.Distinct(ctx, "identity", bson.M{"prayer_request_id": objectID, "type": data.ReactionPray}).Decode(&identities)
+	if err != nil {
+		return nil, err
+	}
+
+	return identities, nil
 }
 
-// GetPrayerRequestsByCategory gets prayer requests by category
-func (r *mongoRepository) GetPrayerRequestsByCategory(ctx context.Context, category string) ([]*data.PrayerRequest, error) {
-	filter := bson.M{"category": category}
+// SavePrayerRequest adds a prayer request to a user's saved list. It
+// reports false, without saving anything, if the user had already saved it.
+func (r *mongoRepository) SavePrayerRequest(ctx context.Context, save *data.SavedPrayer) (bool, error) {
+	filter := bson.M{
+		"prayer_request_id": save.PrayerRequestID,
+		"user_id":           save.UserID,
+	}
+	update := bson.M{"$setOnInsert": bson.M{
+		"_id":        save.ID,
+		"created_at": save.CreatedAt,
+	}}
 
-	cursor, err := r.collection.Find(ctx, filter)
+	result, err := r.saved.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
 	if err != nil {
-		return nil, err
+		return false, err
 	}
-	defer cursor.Close(ctx)
 
-	var requests []*data.PrayerRequest
-	for cursor.Next(ctx) {
-		var req data.PrayerRequest
-		if err := cursor.Decode(&req); err != nil {
-			return nil, err
-		}
-		requests = append(requests, &req)
+	return result.UpsertedCount > 0, nil
+}
+
+// UnsavePrayerRequest removes a prayer request from a user's saved list. It
+// reports whether the request had been saved.
+func (r *mongoRepository) UnsavePrayerRequest(ctx context.Context, prayerID string, userID bson.ObjectID) (bool, error) {
+	objectID, err := bson.ObjectIDFromHex(prayerID)
+	if err != nil {
+		return false, err
 	}
 
-	return requests, cursor.Err()
+	result, err := r.saved.DeleteOne(ctx, bson.M{
+		"prayer_request_id": objectID,
+		"user_id":           userID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+// IncrementSavedCount adds delta to the saved count of a prayer request
+func (r *mongoRepository) IncrementSavedCount(ctx context.Context, id string, delta int) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{"_id": objectID}),
+		bson.M{"$inc": bson.M{"saved_count": delta}},
+	)
+	return err
+}
+
+// GetSavedPrayerRequests retrieves a page of a user's saved prayer requests.
+// Requests in the trash or hidden by moderation are skipped before the page
+// is cut so pages stay full.
+func (r *mongoRepository) GetSavedPrayerRequests(ctx context.Context, userID bson.ObjectID, page pagination.Params) (*pagination.Page[*data.SavedPrayerRequest], error) {
+	pipeline := bson.A{
+		bson.M{"$match": page.Filter(bson.M{"user_id": userID}, savedOrder)},
+		bson.M{"$sort": bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		bson.M{"$lookup": bson.M{
+			"from":         r.collection.Name(),
+			"localField":   "prayer_request_id",
+			"foreignField": "_id",
+			"as":           "prayer_request",
+		}},
+		bson.M{"$unwind": "$prayer_request"},
+		bson.M{"$match": bson.M{
+			"prayer_request.deleted_at":        nil,
+			"prayer_request.moderation_status": moderation.StatusPublished,
+		}},
+		bson.M{"$limit": page.Limit + 1},
+		bson.M{"$replaceRoot": bson.M{"newRoot": bson.M{"$mergeObjects": bson.A{
+			"$prayer_request",
+			bson.M{"saved_at": "$created_at", "save_id": "$_id"},
+		}}}},
+	}
+
+	return pagination.AggregatePage(ctx, r.saved, pipeline, page, savedPrayerRequestCursor)
+}
+
+// SearchPrayerRequests runs a text search over title, description and tags,
+// most relevant first
+func (r *mongoRepository) SearchPrayerRequests(ctx context.Context, query data.SearchQuery, page pagination.Params) (*pagination.Page[*data.SearchResult], error) {
+	match := published(notDeleted(bson.M{"$text": bson.M{"$search": query.Text}}))
+	if query.Category != "" {
+		match["category"] = query.Category
+	}
+	if query.Priority != "" {
+		match["priority"] = query.Priority
+	}
+	if query.IsAnswered != nil {
+		match["is_answered"] = *query.IsAnswered
+	}
+	if !query.IncludeArchived {
+		notArchived(match)
+	}
+
+	pipeline := bson.A{
+		bson.M{"$match": match},
+		bson.M{"$addFields": bson.M{"score": bson.M{"$meta": "textScore"}}},
+	}
+	if page.Cursor != nil {
+		pipeline = append(pipeline, bson.M{"$match": page.Filter(bson.M{}, searchOrder)})
+	}
+	pipeline = append(pipeline,
+		bson.M{"$sort": bson.D{{Key: "score", Value: -1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		bson.M{"$limit": page.Limit + 1},
+	)
+
+	return pagination.AggregatePage(ctx, r.collection, pipeline, page, searchResultCursor)
+}
+
+// GetPrayerRequestsByCategory gets prayer requests by category
+func (r *mongoRepository) GetPrayerRequestsByCategory(ctx context.Context, category string, page pagination.Params) (*pagination.Page[*data.PrayerRequest], error) {
+	filter := published(notArchived(notDeleted(bson.M{"category": category})))
+
+	return pagination.FindPage(ctx, r.collection, filter, page, prayerRequestsDesc, prayerRequestCursor)
 }
 
 // GetRecentPrayerRequests gets recent prayer requests
 func (r *mongoRepository) GetRecentPrayerRequests(ctx context.Context, limit int) ([]*data.PrayerRequest, error) {
 	opts := options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(int64(limit))
-	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	cursor, err := r.collection.Find(ctx, published(notArchived(notDeleted(bson.M{}))), opts)
 	if err != nil {
 		return nil, err
 	}
@@ -192,26 +627,31 @@ func (r *mongoRepository) GetRecentPrayerRequests(ctx context.Context, limit int
 // GetPrayerStats gets prayer statistics
 func (r *mongoRepository) GetPrayerStats(ctx context.Context) (*data.PrayerStats, error) {
 	// Get total count
-	totalCount, err := r.collection.CountDocuments(ctx, bson.M{})
+	totalCount, err := r.collection.CountDocuments(ctx, published(notDeleted(bson.M{})))
 	if err != nil {
 		return nil, err
 	}
 
 	// Get answered prayers count
-	answeredCount, err := r.collection.CountDocuments(ctx, bson.M{"is_answered": true})
+	answeredCount, err := r.collection.CountDocuments(ctx, published(notDeleted(bson.M{"is_answered": true})))
 	if err != nil {
 		return nil, err
 	}
 
 	// Get urgent prayers count
-	urgentCount, err := r.collection.CountDocuments(ctx, bson.M{"priority": "urgent"})
+	urgentCount, err := r.collection.CountDocuments(ctx, published(notDeleted(bson.M{"priority": "urgent"})))
 	if err != nil {
 		return nil, err
 	}
 
-	// Get total pray count using aggregation
+	// Get total pray and comment counts using aggregation
 	pipeline := []bson.M{
-		{"$group": bson.M{"_id": nil, "total": bson.M{"$sum": "$pray_count"}}},
+		{"$match": published(notDeleted(bson.M{}))},
+		{"$group": bson.M{
+			"_id":      nil,
+			"total":    bson.M{"$sum": "$pray_count"},
+			"comments": bson.M{"$sum": "$comment_count"},
+		}},
 	}
 	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
@@ -219,19 +659,22 @@ func (r *mongoRepository) GetPrayerStats(ctx context.Context) (*data.PrayerStats
 	}
 	defer cursor.Close(ctx)
 
-	var totalPrayCount int
+	var totalPrayCount, totalComments int
 	if cursor.Next(ctx) {
 		var result struct {
-			Total int `bson:"total"`
+			Total    int `bson:"total"`
+			Comments int `bson:"comments"`
 		}
 		if err := cursor.Decode(&result); err != nil {
 			return nil, err
 		}
 		totalPrayCount = result.Total
+		totalComments = result.Comments
 	}
 
 	// Get categories count
 	categoryPipeline := []bson.M{
+		{"$match": published(notDeleted(bson.M{}))},
 		{"$group": bson.M{"_id": "$category", "count": bson.M{"$sum": 1}}},
 	}
 	categoryCursor, err := r.collection.Aggregate(ctx, categoryPipeline)
@@ -252,45 +695,499 @@ func (r *mongoRepository) GetPrayerStats(ctx context.Context) (*data.PrayerStats
 		categoriesCount[result.ID] = result.Count
 	}
 
+	// Get locations count, by holy site where there is one and otherwise by
+	// lower-cased place
+	locationPipeline := []bson.M{
+		{"$match": published(notDeleted(bson.M{"location": bson.M{"$exists": true}}))},
+		{"$group": bson.M{
+			"_id":   bson.M{"$ifNull": bson.A{"$location.holy_site", bson.M{"$toLower": "$location.place"}}},
+			"count": bson.M{"$sum": 1},
+		}},
+	}
+	locationCursor, err := r.collection.Aggregate(ctx, locationPipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer locationCursor.Close(ctx)
+
+	locationsCount := make(map[string]int)
+	for locationCursor.Next(ctx) {
+		var result struct {
+			ID    string `bson:"_id"`
+			Count int    `bson:"count"`
+		}
+		if err := locationCursor.Decode(&result); err != nil {
+			return nil, err
+		}
+		locationsCount[result.ID] = result.Count
+	}
+
+	// Get the latest activity
+	activity, err := r.GetActivities(ctx, nil, pagination.Params{Limit: recentActivityLimit})
+	if err != nil {
+		return nil, err
+	}
+
+	recentActivity := make([]data.ActivityItem, 0, len(activity.Items))
+	for _, item := range activity.Items {
+		recentActivity = append(recentActivity, *item)
+	}
+
 	return &data.PrayerStats{
 		TotalPrayers:    int(totalCount),
 		TotalPrayCount:  totalPrayCount,
+		TotalComments:   totalComments,
 		AnsweredPrayers: int(answeredCount),
 		UrgentPrayers:   int(urgentCount),
 		CategoriesCount: categoriesCount,
-		RecentActivity:  []data.ActivityItem{}, // Placeholder for now
+		LocationsCount:  locationsCount,
+		RecentActivity:  recentActivity,
 	}, nil
 }
 
 // CreateComment creates a new comment
 func (r *mongoRepository) CreateComment(ctx context.Context, comment *data.Comment) error {
-	commentsCollection := r.collection.Database().Collection("comments")
-	_, err := commentsCollection.InsertOne(ctx, comment)
+	if _, err := r.comments.InsertOne(ctx, comment); err != nil {
+		return err
+	}
+
+	return r.incrementCommentCount(ctx, comment.PrayerRequestID, commentWeight(comment))
+}
+
+// incrementCommentCount adds delta to the comment count of a prayer
+// request. Requests in the trash are counted too, so they come back right.
+func (r *mongoRepository) incrementCommentCount(ctx context.Context, prayerID bson.ObjectID, delta int) error {
+	if delta == 0 {
+		return nil
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": prayerID}, bson.M{"$inc": bson.M{"comment_count": delta}})
 	return err
 }
 
-// GetCommentsByPrayerID gets comments for a prayer request
-func (r *mongoRepository) GetCommentsByPrayerID(ctx context.Context, prayerID string) ([]*data.Comment, error) {
+// RepairCommentCounts recomputes the comment count of every prayer request
+// from the comments collection and returns how many were wrong
+func (r *mongoRepository) RepairCommentCounts(ctx context.Context) (int, error) {
+	pipeline := bson.A{
+		bson.M{"$lookup": bson.M{
+			"from": "comments",
+			"let":  bson.M{"id": "$_id"},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{
+					"$expr":             bson.M{"$eq": bson.A{"$prayer_request_id", "$$id"}},
+					"deleted_at":        nil,
+					"moderation_status": moderation.StatusPublished,
+				}},
+				bson.M{"$count": "n"},
+			},
+			"as": "counted",
+		}},
+		bson.M{"$project": bson.M{
+			"comment_count": bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{"$counted.n", 0}}, 0}},
+			"stored":        "$comment_count",
+		}},
+		bson.M{"$match": bson.M{"$expr": bson.M{"$ne": bson.A{"$comment_count", "$stored"}}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	repaired := 0
+	for cursor.Next(ctx) {
+		var result struct {
+			ID           bson.ObjectID `bson:"_id"`
+			CommentCount int           `bson:"comment_count"`
+		}
+		if err := cursor.Decode(&result); err != nil {
+			return repaired, err
+		}
+
+		_, err := r.collection.UpdateOne(ctx, bson.M{"_id": result.ID}, bson.M{"$set": bson.M{"comment_count": result.CommentCount}})
+		if err != nil {
+			return repaired, err
+		}
+		repaired++
+	}
+
+	return repaired, cursor.Err()
+}
+
+// GetCommentsByPrayerID gets a page of comments for a prayer request
+func (r *mongoRepository) GetCommentsByPrayerID(ctx context.Context, prayerID string, page pagination.Params) (*pagination.Page[*data.Comment], error) {
 	objectID, err := bson.ObjectIDFromHex(prayerID)
 	if err != nil {
 		return nil, err
 	}
 
-	commentsCollection := r.collection.Database().Collection("comments")
-	cursor, err := commentsCollection.Find(ctx, bson.M{"prayer_request_id": objectID})
+	filter := published(bson.M{"prayer_request_id": objectID})
+
+	return pagination.FindPage(ctx, r.comments, filter, page, commentsDesc, commentCursor)
+}
+
+// GetCommentThreads gets a page of the top-level comments on a prayer request
+func (r *mongoRepository) GetCommentThreads(ctx context.Context, prayerID string, page pagination.Params) (*pagination.Page[*data.Comment], error) {
+	objectID, err := bson.ObjectIDFromHex(prayerID)
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(ctx)
 
-	var comments []*data.Comment
-	for cursor.Next(ctx) {
-		var comment data.Comment
-		if err := cursor.Decode(&comment); err != nil {
-			return nil, err
+	filter := published(bson.M{"prayer_request_id": objectID, "thread_id": nil})
+
+	return pagination.FindPage(ctx, r.comments, filter, page, commentsDesc, commentCursor)
+}
+
+// GetCommentReplies gets every reply in the given threads, oldest first
+func (r *mongoRepository) GetCommentReplies(ctx context.Context, threadIDs []bson.ObjectID) ([]*data.Comment, error) {
+	if len(threadIDs) == 0 {
+		return nil, nil
+	}
+
+	filter := published(bson.M{"thread_id": bson.M{"$in": threadIDs}})
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.comments.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var replies []*data.Comment
+	if err := cursor.All(ctx, &replies); err != nil {
+		return nil, err
+	}
+
+	return replies, nil
+}
+
+// UpdateComment updates a comment. Tombstones of deleted comments are left alone.
+func (r *mongoRepository) UpdateComment(ctx context.Context, id string, comment *data.Comment) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	var before data.Comment
+	err = r.comments.FindOneAndReplace(ctx, notDeleted(bson.M{"_id": objectID}), comment).Decode(&before)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return r.incrementCommentCount(ctx, before.PrayerRequestID, commentWeight(comment)-commentWeight(&before))
+}
+
+// CreateActivity appends an item to the activity log
+func (r *mongoRepository) CreateActivity(ctx context.Context, item *data.ActivityItem) error {
+	_, err := r.activities.InsertOne(ctx, item)
+	return err
+}
+
+// GetActivities gets a page of the activity log, optionally limited to some types
+func (r *mongoRepository) GetActivities(ctx context.Context, types []string, page pagination.Params) (*pagination.Page[*data.ActivityItem], error) {
+	filter := notDeleted(bson.M{})
+	if len(types) > 0 {
+		filter["type"] = bson.M{"$in": types}
+	}
+
+	return pagination.FindPage(ctx, r.activities, filter, page, activitiesDesc, activityCursor)
+}
+
+// TrashPrayerRequest moves a prayer request and its activity to the trash
+func (r *mongoRepository) TrashPrayerRequest(ctx context.Context, id string, deletedAt time.Time) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(ctx,
+		notDeleted(bson.M{"_id": objectID}),
+		bson.M{"$set": bson.M{"deleted_at": deletedAt}},
+	)
+	if err != nil || result.ModifiedCount == 0 {
+		return err
+	}
+
+	_, err = r.activities.UpdateMany(ctx,
+		bson.M{"prayer_request_id": objectID},
+		bson.M{"$set": bson.M{"deleted_at": deletedAt}},
+	)
+	return err
+}
+
+// RestorePrayerRequest takes a prayer request and its activity out of the trash
+func (r *mongoRepository) RestorePrayerRequest(ctx context.Context, id string) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": objectID, "deleted_at": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"deleted_at": ""}},
+	)
+	if err != nil || result.ModifiedCount == 0 {
+		return err
+	}
+
+	_, err = r.activities.UpdateMany(ctx,
+		bson.M{"prayer_request_id": objectID},
+		bson.M{"$unset": bson.M{"deleted_at": ""}},
+	)
+	return err
+}
+
+// GetTrashedPrayerRequestByID retrieves a prayer request that is in the trash
+func (r *mongoRepository) GetTrashedPrayerRequestByID(ctx context.Context, id string) (*data.PrayerRequest, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var req data.PrayerRequest
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID, "deleted_at": bson.M{"$exists": true}}).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+// GetTrashedPrayerRequests retrieves a page of the trash, optionally limited
+// to one user's requests
+func (r *mongoRepository) GetTrashedPrayerRequests(ctx context.Context, userID *bson.ObjectID, page pagination.Params) (*pagination.Page[*data.PrayerRequest], error) {
+	filter := bson.M{"deleted_at": bson.M{"$exists": true}}
+	if userID != nil {
+		filter["user_id"] = *userID
+	}
+
+	return pagination.FindSortedPage(ctx, r.collection, filter, page, trashOrder, trashedPrayerRequestCursor)
+}
+
+// PurgePrayerRequests permanently deletes the prayer requests trashed
+// before the given time, along with their comments, reactions, saves,
+// activity and reports, and whatever purgeOthers deletes for them.
+// Dependents are deleted first so an interrupted purge is picked up again
+// on the next run.
+func (r *mongoRepository) PurgePrayerRequests(ctx context.Context, deletedBefore time.Time, purgeOthers PurgeFunc) (int, error) {
+	purged := 0
+	for {
+		opts := options.Find().SetProjection(bson.M{"_id": 1}).SetLimit(purgeBatchSize)
+		cursor, err := r.collection.Find(ctx, bson.M{"deleted_at": bson.M{"$lt": deletedBefore}}, opts)
+		if err != nil {
+			return purged, err
+		}
+
+		var docs []struct {
+			ID bson.ObjectID `bson:"_id"`
+		}
+		if err := cursor.All(ctx, &docs); err != nil {
+			return purged, err
 		}
-		comments = append(comments, &comment)
+		if len(docs) == 0 {
+			return purged, nil
+		}
+
+		ids := make([]bson.ObjectID, len(docs))
+		for i, doc := range docs {
+			ids[i] = doc.ID
+		}
+
+		if err := purgeOthers(ctx, ids); err != nil {
+			return purged, err
+		}
+
+		dependents := bson.M{"prayer_request_id": bson.M{"$in": ids}}
+		for _, collection := range []*mongo.Collection{r.comments, r.reactions, r.saved, r.activities, r.reports} {
+			if _, err := collection.DeleteMany(ctx, dependents); err != nil {
+				return purged, err
+			}
+		}
+
+		result, err := r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+		if err != nil {
+			return purged, err
+		}
+		purged += int(result.DeletedCount)
+	}
+}
+
+// ArchiveExpiredPrayerRequests archives the prayer requests whose expiry
+// has passed and reports how many
+func (r *mongoRepository) ArchiveExpiredPrayerRequests(ctx context.Context, now time.Time) (int, error) {
+	filter := notArchived(notDeleted(bson.M{"expires_at": bson.M{"$lte": now}}))
+	result, err := r.collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"archived_at": now}})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(result.ModifiedCount), nil
+}
+
+// GetArchivedPrayerRequests retrieves a page of a user's archived prayer
+// requests, leaving out the anonymous ones unless includeAnonymous is set
+func (r *mongoRepository) GetArchivedPrayerRequests(ctx context.Context, userID bson.ObjectID, includeAnonymous bool, page pagination.Params) (*pagination.Page[*data.PrayerRequest], error) {
+	filter := published(notDeleted(bson.M{"user_id": userID, "archived_at": bson.M{"$exists": true}}))
+	if !includeAnonymous {
+		filter["is_anonymous"] = false
+	}
+
+	return pagination.FindSortedPage(ctx, r.collection, filter, page, archiveOrder, archivedPrayerRequestCursor)
+}
+
+// GetAnsweredPrayerRequests retrieves a page of answered prayer requests,
+// optionally limited to one category
+func (r *mongoRepository) GetAnsweredPrayerRequests(ctx context.Context, category string, page pagination.Params) (*pagination.Page[*data.PrayerRequest], error) {
+	filter := published(notArchived(notDeleted(bson.M{"answered_at": bson.M{"$exists": true}})))
+	if category != "" {
+		filter["category"] = category
+	}
+
+	return pagination.FindSortedPage(ctx, r.collection, filter, page, answeredOrder, answeredPrayerRequestCursor)
+}
+
+// moderated returns the collection and base filter of a moderation target.
+// Prayer requests in the trash are left alone.
+func (r *mongoRepository) moderated(target string, id bson.ObjectID) (*mongo.Collection, bson.M) {
+	if target == data.ReportTargetComment {
+		return r.comments, bson.M{"_id": id}
 	}
+	return r.collection, notDeleted(bson.M{"_id": id})
+}
+
+// GetCommentByID retrieves a comment by ID, whatever its moderation status
+func (r *mongoRepository) GetCommentByID(ctx context.Context, id string) (*data.Comment, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var comment data.Comment
+	err = r.comments.FindOne(ctx, bson.M{"_id": objectID}).Decode(&comment)
+	if err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// SetModerationStatus moves a prayer request or comment to a moderation
+// status. Publishing clears the report count, so it takes fresh reports to
+// hold the content again, and records when it was first published.
+func (r *mongoRepository) SetModerationStatus(ctx context.Context, target, id, status string, reasons []string, now time.Time) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	set := bson.M{"moderation_status": status}
+	if status == moderation.StatusPublished {
+		set["report_count"] = 0
+		set["published_at"] = bson.M{"$ifNull": bson.A{"$published_at", now}}
+	}
+	update := bson.A{bson.M{"$set": set}}
+	if len(reasons) > 0 {
+		update = append(update, bson.M{"$set": bson.M{"moderation_reasons": reasons}})
+	} else {
+		update = append(update, bson.M{"$unset": "moderation_reasons"})
+	}
+
+	collection, filter := r.moderated(target, objectID)
+	if target != data.ReportTargetComment {
+		_, err = collection.UpdateOne(ctx, filter, update)
+		return err
+	}
+
+	// A comment going in or out of view changes its request's comment count
+	var before data.Comment
+	err = collection.FindOneAndUpdate(ctx, filter, update).Decode(&before)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	after := before
+	after.ModerationStatus = status
+	return r.incrementCommentCount(ctx, before.PrayerRequestID, commentWeight(&after)-commentWeight(&before))
+}
+
+// CreateReport records a report. It reports false, without recording
+// anything, if the identity had already reported the target.
+func (r *mongoRepository) CreateReport(ctx context.Context, report *data.Report) (bool, error) {
+	filter := bson.M{
+		"target_type": report.TargetType,
+		"target_id":   report.TargetID,
+		"identity":    report.Identity,
+	}
+	update := bson.M{"$setOnInsert": bson.M{
+		"_id":               report.ID,
+		"prayer_request_id": report.PrayerRequestID,
+		"reason":            report.Reason,
+		"details":           report.Details,
+		"created_at":        report.CreatedAt,
+	}}
+
+	result, err := r.reports.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		return false, err
+	}
+
+	return result.UpsertedCount > 0, nil
+}
+
+// IncrementReportCount increments the report count of a prayer request or
+// comment and returns the new count
+func (r *mongoRepository) IncrementReportCount(ctx context.Context, target, id string) (int, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, err
+	}
+
+	collection, filter := r.moderated(target, objectID)
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"report_count": 1})
+
+	var result struct {
+		ReportCount int `bson:"report_count"`
+	}
+	err = collection.FindOneAndUpdate(ctx, filter, bson.M{"$inc": bson.M{"report_count": 1}}, opts).Decode(&result)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ReportCount, nil
+}
+
+// GetReports retrieves a page of the reports on a prayer request or comment
+func (r *mongoRepository) GetReports(ctx context.Context, target, id string, page pagination.Params) (*pagination.Page[*data.Report], error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"target_type": target, "target_id": objectID}
+
+	return pagination.FindPage(ctx, r.reports, filter, page, reportsDesc, reportCursor)
+}
+
+// GetPrayerRequestsByStatus retrieves a page of the prayer requests in a
+// moderation status, oldest first
+func (r *mongoRepository) GetPrayerRequestsByStatus(ctx context.Context, status string, page pagination.Params) (*pagination.Page[*data.PrayerRequest], error) {
+	filter := notDeleted(bson.M{"moderation_status": status})
+
+	return pagination.FindPage(ctx, r.collection, filter, page, queueDesc, prayerRequestCursor)
+}
+
+// GetCommentsByStatus retrieves a page of the comments in a moderation
+// status, oldest first
+func (r *mongoRepository) GetCommentsByStatus(ctx context.Context, status string, page pagination.Params) (*pagination.Page[*data.Comment], error) {
+	filter := bson.M{"moderation_status": status}
 
-	return comments, cursor.Err()
+	return pagination.FindPage(ctx, r.comments, filter, page, queueDesc, commentCursor)
 }
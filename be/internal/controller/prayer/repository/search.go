@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"strings"
+	"unicode"
+
+	"prayerreq-backend/internal/controller/prayer/data"
+)
+
+// Field weights of the text index created by the database migrations
+const (
+	titleWeight       = 10
+	tagsWeight        = 5
+	descriptionWeight = 1
+)
+
+// textQuery is a parsed MongoDB text search string, used by the in-memory
+// repository to approximate $text matching and textScore ranking
+type textQuery struct {
+	terms          []string // stemmed; a document must contain at least one
+	phrases        []string // lower-cased; a document must contain all of them
+	negatedTerms   []string // stemmed; a document must contain none of them
+	negatedPhrases []string // lower-cased
+	scoredTerms    []string // terms and the words of phrases, used for ranking
+	onlyNegated    bool
+}
+
+// parseTextQuery parses words, "quoted phrases" and -negations the way
+// MongoDB's $search string does
+func parseTextQuery(s string) textQuery {
+	var q textQuery
+	for len(s) > 0 {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		if s == "" {
+			break
+		}
+
+		negated := strings.HasPrefix(s, "-")
+		if negated {
+			s = s[1:]
+		}
+
+		if strings.HasPrefix(s, `"`) {
+			phrase, rest, _ := strings.Cut(s[1:], `"`)
+			s = rest
+			phrase = strings.ToLower(strings.TrimSpace(phrase))
+			if phrase == "" {
+				continue
+			}
+			if negated {
+				q.negatedPhrases = append(q.negatedPhrases, phrase)
+			} else {
+				q.phrases = append(q.phrases, phrase)
+				q.scoredTerms = append(q.scoredTerms, tokenize(phrase)...)
+			}
+			continue
+		}
+
+		end := strings.IndexFunc(s, unicode.IsSpace)
+		if end < 0 {
+			end = len(s)
+		}
+		words := tokenize(s[:end])
+		s = s[end:]
+		if negated {
+			q.negatedTerms = append(q.negatedTerms, words...)
+		} else {
+			q.terms = append(q.terms, words...)
+			q.scoredTerms = append(q.scoredTerms, words...)
+		}
+	}
+
+	q.onlyNegated = len(q.terms) == 0 && len(q.phrases) == 0
+	return q
+}
+
+// score returns the relevance of a prayer request, or 0 if it doesn't
+// match. Like MongoDB, a query with phrases only requires the phrases; the
+// other terms then just affect the ranking.
+func (q textQuery) score(req *data.PrayerRequest) float64 {
+	if q.onlyNegated {
+		return 0
+	}
+
+	fields := []struct {
+		text   string
+		weight float64
+	}{
+		{req.Title, titleWeight},
+		{strings.Join(req.Tags, " "), tagsWeight},
+		{req.Description, descriptionWeight},
+	}
+
+	var all strings.Builder
+	for _, f := range fields {
+		all.WriteString(strings.ToLower(f.text))
+		all.WriteString("\n")
+	}
+	text := all.String()
+
+	for _, phrase := range q.negatedPhrases {
+		if strings.Contains(text, phrase) {
+			return 0
+		}
+	}
+	for _, phrase := range q.phrases {
+		if !strings.Contains(text, phrase) {
+			return 0
+		}
+	}
+
+	words := make(map[string]bool)
+	for _, word := range tokenize(text) {
+		words[word] = true
+	}
+	for _, term := range q.negatedTerms {
+		if words[term] {
+			return 0
+		}
+	}
+	if len(q.phrases) == 0 && !containsAny(words, q.terms) {
+		return 0
+	}
+
+	var score float64
+	for _, f := range fields {
+		fieldWords := tokenize(f.text)
+		if len(fieldWords) == 0 {
+			continue
+		}
+
+		matches := 0
+		for _, word := range fieldWords {
+			for _, term := range q.scoredTerms {
+				if word == term {
+					matches++
+				}
+			}
+		}
+		// Matches in short fields count for more, as they do in MongoDB
+		score += f.weight * float64(matches) / (0.5 + 0.5*float64(len(fieldWords)))
+	}
+
+	return score
+}
+
+// containsAny reports whether any of the terms is in the set of words
+func containsAny(words map[string]bool, terms []string) bool {
+	for _, term := range terms {
+		if words[term] {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenize splits text into lower-cased, stemmed words
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, field := range fields {
+		fields[i] = stem(field)
+	}
+	return fields
+}
+
+// stem strips common English suffixes so "praying" matches "pray". It is a
+// rough stand-in for the Snowball stemmer MongoDB uses.
+func stem(word string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= 3 {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
@@ -1,6 +1,9 @@
 package prayer
 
 import (
+	"prayerreq-backend/internal/apierror"
+	"prayerreq-backend/internal/auth"
+
 	"github.com/go-chi/chi/v5"
 )
 
@@ -27,6 +30,15 @@ func (h *HTTPHandler) RegisterRoutes(r chi.Router) {
 		r.Get("/search", h.service.SearchPrayers)
 		r.Get("/stats", h.service.GetPrayerStats)
 		r.Get("/recent", h.service.GetRecentPrayers)
+		r.Get("/activity", h.service.GetActivity)
+		r.With(auth.RequireUser).Get("/trash", h.service.GetTrash)
+		r.With(auth.RequireUser).Get("/archived", h.service.GetArchive)
+		r.Get("/answered", h.service.GetAnsweredPrayers)
+		if h.service.features.LiveStream {
+			r.Get("/stream", h.service.StreamPrayers)
+		} else {
+			r.Get("/stream", apierror.FeatureDisabled)
+		}
 
 		// Category routes
 		r.Route("/category", func(r chi.Router) {
@@ -36,11 +48,29 @@ func (h *HTTPHandler) RegisterRoutes(r chi.Router) {
 		// Individual prayer operations - these should be last
 		r.Route("/{id}", func(r chi.Router) {
 			r.Get("/", h.service.GetPrayerByID)
-			r.Put("/", h.service.UpdatePrayer)
-			r.Delete("/", h.service.DeletePrayer)
+			r.With(auth.RequireUser).Put("/", h.service.UpdatePrayer)
+			r.With(auth.RequireUser).Delete("/", h.service.DeletePrayer)
+			r.With(auth.RequireUser).Post("/restore", h.service.RestorePrayer)
+			r.With(auth.RequireUser).Post("/answer", h.service.AnswerPrayer)
 			r.Post("/pray", h.service.IncrementPrayCount)
-			r.Post("/comments", h.service.AddComment)
-			r.Get("/comments", h.service.GetComments)
+			r.Get("/prayed", h.service.HasPrayed)
+			r.Get("/reactions", h.service.GetReactions)
+			r.Post("/reactions", h.service.AddReaction)
+			r.Delete("/reactions/{type}", h.service.RemoveReaction)
+			r.With(auth.RequireUser).Post("/save", h.service.SavePrayer)
+			r.With(auth.RequireUser).Delete("/save", h.service.UnsavePrayer)
+			r.Post("/report", h.service.ReportPrayer)
+
+			r.Route("/comments", func(r chi.Router) {
+				r.Post("/", h.service.AddComment)
+				r.Get("/", h.service.GetComments)
+				r.With(auth.RequireUser).Put("/{commentId}", h.service.UpdateComment)
+				r.With(auth.RequireUser).Delete("/{commentId}", h.service.DeleteComment)
+			})
 		})
 	})
+
+	r.Route("/comments", func(r chi.Router) {
+		r.Post("/{id}/report", h.service.ReportComment)
+	})
 }
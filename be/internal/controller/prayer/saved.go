@@ -0,0 +1,80 @@
+package prayer
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"prayerreq-backend/internal/apierror"
+	"prayerreq-backend/internal/auth"
+	"prayerreq-backend/internal/controller/prayer/data"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// SavePrayer handles POST /api/v1/prayers/{id}/save
+// Saving a request that is already saved leaves the count unchanged.
+func (s *Service) SavePrayer(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	user := auth.UserFromContext(r.Context())
+
+	request, err := s.getVisiblePrayer(r, id)
+	if err != nil {
+		apierror.Write(w, r, prayerNotFound(err))
+		return
+	}
+
+	saved, err := s.repo.SavePrayerRequest(r.Context(), &data.SavedPrayer{
+		ID:              bson.NewObjectID(),
+		PrayerRequestID: request.ID,
+		UserID:          user.ID,
+		CreatedAt:       time.Now(),
+	})
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	result := data.SaveResult{Saved: true, SavedCount: request.SavedCount}
+	if saved {
+		if err := s.repo.IncrementSavedCount(r.Context(), id, 1); err != nil {
+			apierror.Write(w, r, apierror.Internal(err))
+			return
+		}
+		result.SavedCount++
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// UnsavePrayer handles DELETE /api/v1/prayers/{id}/save
+func (s *Service) UnsavePrayer(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	user := auth.UserFromContext(r.Context())
+
+	request, err := s.repo.GetPrayerRequestByID(r.Context(), id)
+	if err != nil {
+		apierror.Write(w, r, prayerNotFound(err))
+		return
+	}
+
+	unsaved, err := s.repo.UnsavePrayerRequest(r.Context(), id, user.ID)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	result := data.SaveResult{Saved: false, SavedCount: request.SavedCount}
+	if unsaved {
+		if err := s.repo.IncrementSavedCount(r.Context(), id, -1); err != nil {
+			apierror.Write(w, r, apierror.Internal(err))
+			return
+		}
+		result.SavedCount--
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -1,85 +1,157 @@
 package prayer
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"log"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"prayerreq-backend/internal/apierror"
+	"prayerreq-backend/internal/auth"
+	"prayerreq-backend/internal/config"
 	"prayerreq-backend/internal/controller/prayer/data"
 	"prayerreq-backend/internal/controller/prayer/repository"
+	"prayerreq-backend/internal/events"
+	"prayerreq-backend/internal/moderation"
+	"prayerreq-backend/internal/pagination"
+	"prayerreq-backend/internal/validate"
 
 	"github.com/go-chi/chi/v5"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
+// Notifier delivers notifications to the identities that prayed for a
+// request, and deletes them when the request is purged
+type Notifier interface {
+	Notify(ctx context.Context, identities []string, notificationType string, prayerRequestID bson.ObjectID, message string) error
+	DeletePrayerNotifications(ctx context.Context, prayerRequestIDs []bson.ObjectID) error
+}
+
 // Service handles prayer request business logic
 type Service struct {
-	repo repository.Repository
+	repo      repository.Repository
+	events    *events.Broker
+	notifier  Notifier
+	moderator *moderation.Moderator
+	features  config.Features
 }
 
 // NewService creates a new prayer service
-func NewService(repo repository.Repository) *Service {
+func NewService(repo repository.Repository, broker *events.Broker, notifier Notifier, moderator *moderation.Moderator, features config.Features) *Service {
 	return &Service{
-		repo: repo,
+		repo:      repo,
+		events:    broker,
+		notifier:  notifier,
+		moderator: moderator,
+		features:  features,
 	}
 }
 
-// GetPrayers handles GET /api/v1/prayers
+// GetPrayers handles GET /api/v1/prayers with the filter and sort parameters
+// parsed by parsePrayerFilter, and limit and cursor
 func (s *Service) GetPrayers(w http.ResponseWriter, r *http.Request) {
-	prayers, err := s.repo.GetPrayerRequests(r.Context())
+	filter, err := parsePrayerFilter(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	page, err := pagination.FromRequest(r)
 	if err != nil {
-		http.Error(w, "Failed to get prayers: "+err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, r, err)
+		return
+	}
+	if page.Cursor != nil && page.Cursor.Sort != filter.Sort {
+		apierror.Write(w, r, pagination.ErrInvalidCursor)
+		return
+	}
+
+	prayers, err := s.repo.GetPrayerRequests(r.Context(), filter, page)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(prayers)
+	json.NewEncoder(w).Encode(shownPage(r.Context(), prayers))
 }
 
 // CreatePrayer handles POST /api/v1/prayers
 func (s *Service) CreatePrayer(w http.ResponseWriter, r *http.Request) {
 	var input data.CreatePrayerRequestInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		apierror.Write(w, r, apierror.InvalidJSON(err))
+		return
+	}
+	if err := validate.Struct(input); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+	if err := checkExpiry(input.ExpiresAt); err != nil {
+		apierror.Write(w, r, err)
 		return
 	}
 
 	// Create prayer request
 	prayer := &data.PrayerRequest{
-		ID:          bson.NewObjectID(),
-		Title:       input.Title,
-		Description: input.Description,
-		UserName:    input.UserName,
-		IsAnonymous: input.IsAnonymous,
-		Priority:    input.Priority,
-		Category:    input.Category,
-		Tags:        input.Tags,
-		PrayCount:   0,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		ID:             bson.NewObjectID(),
+		Title:          input.Title,
+		Description:    input.Description,
+		UserName:       input.UserName,
+		IsAnonymous:    input.IsAnonymous,
+		Priority:       input.Priority,
+		Category:       input.Category,
+		Tags:           input.Tags,
+		Location:       input.Location,
+		ExpiresAt:      input.ExpiresAt,
+		PrayCount:      0,
+		ReactionCounts: map[string]int{},
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	// Requests made while logged in belong to the user
+	if user := auth.UserFromContext(r.Context()); user != nil {
+		prayer.UserID = user.ID
+		if prayer.UserName == "" && !prayer.IsAnonymous {
+			prayer.UserName = user.Name
+		}
 	}
 
+	// Flagged requests are held for review and announced once approved
+	prayer.Moderation = s.screen(r.Context(), prayerText(prayer)...)
+
 	if err := s.repo.CreatePrayerRequest(r.Context(), prayer); err != nil {
-		http.Error(w, "Failed to create prayer: "+err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, r, apierror.Internal(err))
 		return
 	}
 
+	if isPublished(prayer.Moderation) {
+		s.recordEvent(r.Context(), data.ActivityPrayerCreated, prayer,
+			fmt.Sprintf("New prayer request: %q", prayer.Title), prayer)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(prayer)
+	json.NewEncoder(w).Encode(shown(r.Context(), prayer))
 }
 
 // GetPrayerByID handles GET /api/v1/prayers/{id}
 func (s *Service) GetPrayerByID(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	prayer, err := s.repo.GetPrayerRequestByID(r.Context(), id)
+	prayer, err := s.getVisiblePrayer(r, id)
 	if err != nil {
-		http.Error(w, "Prayer not found: "+err.Error(), http.StatusNotFound)
+		apierror.Write(w, r, prayerNotFound(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(prayer)
+	json.NewEncoder(w).Encode(shown(r.Context(), prayer))
 }
 
 // UpdatePrayer handles PUT /api/v1/prayers/{id}
@@ -89,52 +161,77 @@ func (s *Service) UpdatePrayer(w http.ResponseWriter, r *http.Request) {
 	// Get existing prayer
 	prayer, err := s.repo.GetPrayerRequestByID(r.Context(), id)
 	if err != nil {
-		http.Error(w, "Prayer not found: "+err.Error(), http.StatusNotFound)
+		apierror.Write(w, r, prayerNotFound(err))
 		return
 	}
 
-	var input data.UpdatePrayerRequestInput
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+	if !isOwner(r, prayer) {
+		apierror.Write(w, r, apierror.Forbidden(CodeNotOwner, "Only the owner can update this prayer request"))
 		return
 	}
 
-	// Update fields if provided
-	if input.Title != nil {
-		prayer.Title = *input.Title
-	}
-	if input.Description != nil {
-		prayer.Description = *input.Description
+	var input data.UpdatePrayerRequestInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		apierror.Write(w, r, apierror.InvalidJSON(err))
+		return
 	}
-	if input.IsAnswered != nil {
-		prayer.IsAnswered = *input.IsAnswered
+	if err := validate.Struct(input); err != nil {
+		apierror.Write(w, r, err)
+		return
 	}
-	if input.Priority != nil {
-		prayer.Priority = *input.Priority
+	if err := checkExpiry(input.ExpiresAt); err != nil {
+		apierror.Write(w, r, err)
+		return
 	}
-	if input.Category != nil {
-		prayer.Category = *input.Category
+
+	update := &data.PrayerRequestUpdate{
+		Title:       input.Title,
+		Description: input.Description,
+		Priority:    input.Priority,
+		Category:    input.Category,
+		Tags:        input.Tags,
+		Location:    input.Location,
+		ExpiresAt:   input.ExpiresAt, // extending an archived request brings it back
+		UpdatedAt:   time.Now(),
 	}
-	if input.Tags != nil {
-		prayer.Tags = input.Tags
+
+	// Edits are screened too, but passing the filter doesn't undo a
+	// moderator's decision
+	update.Apply(prayer)
+	if screened := s.screen(r.Context(), prayerText(prayer)...); !isPublished(screened) {
+		update.ModerationStatus = &screened.ModerationStatus
+		update.ModerationReasons = screened.ModerationReasons
 	}
-	prayer.UpdatedAt = time.Now()
 
-	if err := s.repo.UpdatePrayerRequest(r.Context(), id, prayer); err != nil {
-		http.Error(w, "Failed to update prayer: "+err.Error(), http.StatusInternalServerError)
+	prayer, err = s.repo.UpdatePrayerRequest(r.Context(), id, update)
+	if err != nil {
+		apierror.Write(w, r, prayerNotFound(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(prayer)
+	json.NewEncoder(w).Encode(shown(r.Context(), prayer))
 }
 
 // DeletePrayer handles DELETE /api/v1/prayers/{id}
 func (s *Service) DeletePrayer(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	if err := s.repo.DeletePrayerRequest(r.Context(), id); err != nil {
-		http.Error(w, "Failed to delete prayer: "+err.Error(), http.StatusInternalServerError)
+	prayer, err := s.repo.GetPrayerRequestByID(r.Context(), id)
+	if err != nil {
+		apierror.Write(w, r, prayerNotFound(err))
+		return
+	}
+
+	if !canManage(r, prayer) {
+		apierror.Write(w, r, apierror.Forbidden(CodeNotOwner, "Only the owner can delete this prayer request"))
+		return
+	}
+
+	// Deleting moves the request to the trash, where it can be restored
+	// until PurgeTrash removes it for good
+	if err := s.repo.TrashPrayerRequest(r.Context(), id, time.Now()); err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
 		return
 	}
 
@@ -142,65 +239,160 @@ func (s *Service) DeletePrayer(w http.ResponseWriter, r *http.Request) {
 }
 
 // IncrementPrayCount handles POST /api/v1/prayers/{id}/pray
+// Each identity (a user or an anonymous device) is counted once per request.
+// Praying is the "pray" reaction, so it can be undone through the reactions.
 func (s *Service) IncrementPrayCount(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	if err := s.repo.IncrementPrayCount(r.Context(), id); err != nil {
-		http.Error(w, "Failed to increment pray count: "+err.Error(), http.StatusInternalServerError)
+	identity, err := auth.Identity(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	request, err := s.getVisiblePrayer(r, id)
+	if err != nil {
+		apierror.Write(w, r, prayerNotFound(err))
+		return
+	}
+
+	counted, err := s.react(r, request, identity, data.ReactionPray)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"message": "Prayer count incremented"}`))
+	result := data.PrayResult{
+		Message:   "Already prayed for this request",
+		Prayed:    true,
+		Counted:   counted,
+		PrayCount: request.PrayCount,
+	}
+	if counted {
+		result.Message = "Prayer count incremented"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
 }
 
-// SearchPrayers handles GET /api/v1/prayers/search?q=query
+// HasPrayed handles GET /api/v1/prayers/{id}/prayed
+func (s *Service) HasPrayed(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if _, err := s.getVisiblePrayer(r, id); err != nil {
+		apierror.Write(w, r, prayerNotFound(err))
+		return
+	}
+
+	var status data.PrayedStatus
+	if identity, err := auth.Identity(r); err == nil {
+		reactions, err := s.repo.GetIdentityReactions(r.Context(), id, identity)
+		if err != nil {
+			apierror.Write(w, r, apierror.Internal(err))
+			return
+		}
+		status.Prayed = slices.Contains(reactions, data.ReactionPray)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// SearchPrayers handles GET /api/v1/prayers/search?q=&category=&priority=&is_answered=&limit=&cursor=
 func (s *Service) SearchPrayers(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query().Get("q")
-	if query == "" {
-		http.Error(w, "Query parameter 'q' is required", http.StatusBadRequest)
+	params := r.URL.Query()
+	query := data.SearchQuery{
+		Text:     strings.TrimSpace(params.Get("q")),
+		Category: params.Get("category"),
+		Priority: params.Get("priority"),
+	}
+	if query.Text == "" {
+		apierror.Write(w, r, apierror.BadRequest(CodeMissingQuery, "Query parameter 'q' is required"))
+		return
+	}
+	if answered := params.Get("is_answered"); answered != "" {
+		isAnswered, err := strconv.ParseBool(answered)
+		if err != nil {
+			apierror.Write(w, r, invalidParameter("is_answered must be true or false"))
+			return
+		}
+		query.IsAnswered = &isAnswered
+	}
+	if value := params.Get("include_archived"); value != "" {
+		includeArchived, err := strconv.ParseBool(value)
+		if err != nil {
+			apierror.Write(w, r, invalidParameter("include_archived must be true or false"))
+			return
+		}
+		query.IncludeArchived = includeArchived
+	}
+	if err := validate.Struct(query); err != nil {
+		apierror.Write(w, r, err)
 		return
 	}
 
-	prayers, err := s.repo.SearchPrayerRequests(r.Context(), query)
+	page, err := pagination.FromRequest(r)
 	if err != nil {
-		http.Error(w, "Failed to search prayers: "+err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, r, err)
 		return
 	}
 
+	results, err := s.repo.SearchPrayerRequests(r.Context(), query, page)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	for _, result := range results.Items {
+		result.PrayerRequest = *shown(r.Context(), &result.PrayerRequest)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(prayers)
+	json.NewEncoder(w).Encode(results)
 }
 
-// GetPrayersByCategory handles GET /api/v1/prayers/category/{category}
+// GetPrayersByCategory handles GET /api/v1/prayers/category/{category}?limit=&cursor=
 func (s *Service) GetPrayersByCategory(w http.ResponseWriter, r *http.Request) {
 	category := chi.URLParam(r, "category")
 
-	prayers, err := s.repo.GetPrayerRequestsByCategory(r.Context(), category)
+	page, err := pagination.FromRequest(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	prayers, err := s.repo.GetPrayerRequestsByCategory(r.Context(), category, page)
 	if err != nil {
-		http.Error(w, "Failed to get prayers by category: "+err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, r, apierror.Internal(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(prayers)
+	json.NewEncoder(w).Encode(shownPage(r.Context(), prayers))
 }
 
 // GetRecentPrayers handles GET /api/v1/prayers/recent?limit=10
+// The limit follows the rules of the paginated listings.
 func (s *Service) GetRecentPrayers(w http.ResponseWriter, r *http.Request) {
-	limitStr := r.URL.Query().Get("limit")
 	limit := 10 // default
-	if limitStr != "" {
-		if parsedLimit, err := json.Number(limitStr).Int64(); err == nil {
-			limit = int(parsedLimit)
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			apierror.Write(w, r, pagination.ErrInvalidLimit)
+			return
 		}
+		limit = min(parsed, pagination.MaxLimit)
 	}
 
 	prayers, err := s.repo.GetRecentPrayerRequests(r.Context(), limit)
 	if err != nil {
-		http.Error(w, "Failed to get recent prayers: "+err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, r, apierror.Internal(err))
 		return
 	}
+	for i, prayer := range prayers {
+		prayers[i] = shown(r.Context(), prayer)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(prayers)
@@ -210,7 +402,7 @@ func (s *Service) GetRecentPrayers(w http.ResponseWriter, r *http.Request) {
 func (s *Service) GetPrayerStats(w http.ResponseWriter, r *http.Request) {
 	stats, err := s.repo.GetPrayerStats(r.Context())
 	if err != nil {
-		http.Error(w, "Failed to get prayer stats: "+err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, r, apierror.Internal(err))
 		return
 	}
 
@@ -218,58 +410,174 @@ func (s *Service) GetPrayerStats(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(stats)
 }
 
+// GetActivity handles GET /api/v1/prayers/activity?type=&limit=&cursor=
+// The type parameter may be repeated or comma separated.
+func (s *Service) GetActivity(w http.ResponseWriter, r *http.Request) {
+	types := splitValues(r.URL.Query(), "type")
+	for _, t := range types {
+		if !slices.Contains(data.ActivityTypes, t) {
+			apierror.Write(w, r, apierror.BadRequest(CodeUnknownActivityType, "Unknown activity type: "+t))
+			return
+		}
+	}
+
+	page, err := pagination.FromRequest(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	activity, err := s.repo.GetActivities(r.Context(), types, page)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(activity)
+}
+
 // AddComment handles POST /api/v1/prayers/{id}/comments
 func (s *Service) AddComment(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	var input data.CreateCommentInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		apierror.Write(w, r, apierror.InvalidJSON(err))
 		return
 	}
-
-	// Validate prayer request exists
-	_, err := s.repo.GetPrayerRequestByID(r.Context(), id)
-	if err != nil {
-		http.Error(w, "Prayer request not found: "+err.Error(), http.StatusNotFound)
+	if err := validate.Struct(input); err != nil {
+		apierror.Write(w, r, err)
 		return
 	}
 
-	prayerID, err := bson.ObjectIDFromHex(id)
+	// Validate prayer request exists
+	request, err := s.getVisiblePrayer(r, id)
 	if err != nil {
-		http.Error(w, "Invalid prayer ID: "+err.Error(), http.StatusBadRequest)
+		apierror.Write(w, r, prayerNotFound(err))
 		return
 	}
 
 	comment := &data.Comment{
 		ID:              bson.NewObjectID(),
-		PrayerRequestID: prayerID,
+		PrayerRequestID: request.ID,
 		UserName:        input.UserName,
 		Message:         input.Message,
 		IsAnonymous:     input.IsAnonymous,
 		CreatedAt:       time.Now(),
 	}
+	if input.ParentID != nil {
+		parent, err := s.repo.GetCommentByID(r.Context(), input.ParentID.Hex())
+		if err != nil || parent.PrayerRequestID != request.ID || parent.DeletedAt != nil || !isPublished(parent.Moderation) {
+			apierror.Write(w, r, invalidParameter("parent_id must be a comment on this prayer request"))
+			return
+		}
+		comment.ParentID = &parent.ID
+		comment.ThreadID = threadOf(parent)
+	}
+	if user := auth.UserFromContext(r.Context()); user != nil {
+		comment.UserID = user.ID
+	}
+	comment.Moderation = s.screen(r.Context(), comment.UserName, comment.Message)
 
 	if err := s.repo.CreateComment(r.Context(), comment); err != nil {
-		http.Error(w, "Failed to create comment: "+err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, r, apierror.Internal(err))
 		return
 	}
 
+	if isPublished(comment.Moderation) {
+		s.recordEvent(r.Context(), data.ActivityCommentAdded, request,
+			fmt.Sprintf("New comment on %q", request.Title), comment)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(comment)
 }
 
-// GetComments handles GET /api/v1/prayers/{id}/comments
+// GetComments handles GET /api/v1/prayers/{id}/comments?view=&limit=&cursor=
+// The flat view (the default) pages through every comment; the tree view
+// pages through the top-level comments and nests all their replies.
 func (s *Service) GetComments(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	comments, err := s.repo.GetCommentsByPrayerID(r.Context(), id)
+	page, err := pagination.FromRequest(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	view := r.URL.Query().Get("view")
+	if view != "" && view != data.CommentViewFlat && view != data.CommentViewTree {
+		apierror.Write(w, r, invalidParameter("view must be flat or tree"))
+		return
+	}
+
+	// Comments of trashed or hidden requests are hidden with the request
+	if _, err := s.getVisiblePrayer(r, id); err != nil {
+		apierror.Write(w, r, prayerNotFound(err))
+		return
+	}
+
+	var comments *pagination.Page[*data.Comment]
+	if view == data.CommentViewTree {
+		comments, err = s.getCommentTree(r.Context(), id, page)
+	} else {
+		comments, err = s.repo.GetCommentsByPrayerID(r.Context(), id, page)
+	}
 	if err != nil {
-		http.Error(w, "Failed to get comments: "+err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, r, prayerNotFound(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(comments)
 }
+
+// recordEvent appends an event to the activity log and publishes the
+// payload to live stream subscribers. Both are best-effort: a failure is
+// logged rather than failing the request.
+func (s *Service) recordEvent(ctx context.Context, activityType string, request *data.PrayerRequest, message string, payload any) {
+	item := &data.ActivityItem{
+		ID:              bson.NewObjectID(),
+		Type:            activityType,
+		PrayerRequestID: request.ID,
+		Message:         message,
+		CreatedAt:       time.Now(),
+	}
+
+	if err := s.repo.CreateActivity(ctx, item); err != nil {
+		log.Printf("Failed to record %s activity for %s: %v", activityType, request.ID.Hex(), err)
+	}
+
+	s.events.Publish(activityType, request.Category, payload)
+}
+
+// isOwner reports whether the authenticated user created the prayer request.
+// Anonymous requests have no owner and can't be changed by anyone.
+func isOwner(r *http.Request, prayer *data.PrayerRequest) bool {
+	user := auth.UserFromContext(r.Context())
+	return user != nil && !prayer.UserID.IsZero() && prayer.UserID == user.ID
+}
+
+// shown returns the prayer request as the caller sees it
+func shown(ctx context.Context, prayer *data.PrayerRequest) *data.PrayerRequest {
+	var userID bson.ObjectID
+	if user := auth.UserFromContext(ctx); user != nil {
+		userID = user.ID
+	}
+	return prayer.ShownTo(userID, auth.IsStaff(ctx))
+}
+
+// shownPage returns a page of prayer requests as the caller sees them
+func shownPage(ctx context.Context, page *pagination.Page[*data.PrayerRequest]) *pagination.Page[*data.PrayerRequest] {
+	for i, prayer := range page.Items {
+		page.Items[i] = shown(ctx, prayer)
+	}
+	return page
+}
+
+// canManage reports whether the caller owns the prayer request or is an administrator
+func canManage(r *http.Request, prayer *data.PrayerRequest) bool {
+	return isOwner(r, prayer) || auth.IsAdmin(r.Context())
+}
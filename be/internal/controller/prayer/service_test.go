@@ -0,0 +1,345 @@
+package prayer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"prayerreq-backend/internal/auth"
+	"prayerreq-backend/internal/config"
+	"prayerreq-backend/internal/controller/prayer/data"
+	"prayerreq-backend/internal/controller/prayer/repository"
+	userData "prayerreq-backend/internal/controller/user/data"
+	"prayerreq-backend/internal/events"
+	"prayerreq-backend/internal/moderation"
+	"prayerreq-backend/internal/pagination"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// discardNotifier drops every notification
+type discardNotifier struct{}
+
+func (discardNotifier) Notify(ctx context.Context, identities []string, notificationType string, prayerRequestID bson.ObjectID, message string) error {
+	return nil
+}
+
+func (discardNotifier) DeletePrayerNotifications(ctx context.Context, prayerRequestIDs []bson.ObjectID) error {
+	return nil
+}
+
+// blockedWord is held for review by the test routers' filter
+const blockedWord = "scam"
+
+// newTestRouter serves the prayer routes from an in-memory repository
+func newTestRouter(t *testing.T) http.Handler {
+	t.Helper()
+	r, _, _ := newTestService(t)
+	return r
+}
+
+// newTestService is newTestRouter that also returns the service and its
+// repository
+func newTestService(t *testing.T) (http.Handler, *Service, repository.Repository) {
+	t.Helper()
+	broker := events.NewBroker(100)
+	t.Cleanup(broker.Close)
+
+	repo := repository.NewMemoryRepository()
+	filter := moderation.NewWordlistFilter([]string{blockedWord}, 5)
+	service := NewService(repo, broker, discardNotifier{},
+		moderation.NewModerator(filter, 0), config.Features{})
+
+	r := chi.NewRouter()
+	NewHTTPHandler(service).RegisterRoutes(r)
+	return r, service, repo
+}
+
+func newUser(role string) *userData.User {
+	return &userData.User{ID: bson.NewObjectID(), Name: "Member " + role, IsActive: true, Role: role}
+}
+
+// serve sends a request as the user, or anonymously when user is nil
+func serve(t *testing.T, h http.Handler, user *userData.User, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if user != nil {
+		req = req.WithContext(auth.WithUser(req.Context(), user))
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// decode reads a JSON response into a map
+func decode(t *testing.T, rec *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	var body map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response %q: %v", rec.Body.String(), err)
+	}
+	return body
+}
+
+// errorCode returns the code of an error response
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	apiErr, _ := decode(t, rec)["error"].(map[string]any)
+	code, _ := apiErr["code"].(string)
+	return code
+}
+
+// createPrayer creates a prayer request as the user and returns its ID
+func createPrayer(t *testing.T, h http.Handler, user *userData.User, body string) string {
+	t.Helper()
+	rec := serve(t, h, user, http.MethodPost, "/prayers", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create prayer: status %d, body %s", rec.Code, rec.Body.String())
+	}
+	return decode(t, rec)["id"].(string)
+}
+
+func TestUpdatePrayerOnlyByOwner(t *testing.T) {
+	h := newTestRouter(t)
+	owner, other, admin := newUser(userData.RoleMember), newUser(userData.RoleMember), newUser(userData.RoleAdmin)
+	id := createPrayer(t, h, owner, `{"title":"Shifa","description":"For my mother"}`)
+	update := `{"title":"Shifa for my mother"}`
+
+	if rec := serve(t, h, nil, http.MethodPut, "/prayers/"+id, update); rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous update: status %d, want 401", rec.Code)
+	}
+	for name, user := range map[string]*userData.User{"other member": other, "administrator": admin} {
+		rec := serve(t, h, user, http.MethodPut, "/prayers/"+id, update)
+		if rec.Code != http.StatusForbidden || errorCode(t, rec) != CodeNotOwner {
+			t.Errorf("%s update: status %d, want 403 %s", name, rec.Code, CodeNotOwner)
+		}
+	}
+
+	rec := serve(t, h, owner, http.MethodPut, "/prayers/"+id, update)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("owner update: status %d, body %s", rec.Code, rec.Body.String())
+	}
+	if title := decode(t, rec)["title"]; title != "Shifa for my mother" {
+		t.Errorf("title = %v, want the update", title)
+	}
+
+	if rec := serve(t, h, owner, http.MethodPut, "/prayers/"+id, `{"title":"  "}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("blank title: status %d, want 400", rec.Code)
+	}
+}
+
+func TestDeletePrayerByOwnerOrAdmin(t *testing.T) {
+	h := newTestRouter(t)
+	owner, other, moderator, admin := newUser(userData.RoleMember), newUser(userData.RoleMember),
+		newUser(userData.RoleModerator), newUser(userData.RoleAdmin)
+
+	first := createPrayer(t, h, owner, `{"title":"Rizq","description":"A new job"}`)
+	for name, user := range map[string]*userData.User{"other member": other, "moderator": moderator} {
+		if rec := serve(t, h, user, http.MethodDelete, "/prayers/"+first, ""); rec.Code != http.StatusForbidden {
+			t.Errorf("%s delete: status %d, want 403", name, rec.Code)
+		}
+	}
+	if rec := serve(t, h, owner, http.MethodDelete, "/prayers/"+first, ""); rec.Code != http.StatusNoContent {
+		t.Errorf("owner delete: status %d, want 204", rec.Code)
+	}
+
+	second := createPrayer(t, h, owner, `{"title":"Rizq","description":"A new home"}`)
+	if rec := serve(t, h, admin, http.MethodDelete, "/prayers/"+second, ""); rec.Code != http.StatusNoContent {
+		t.Errorf("admin delete: status %d, want 204", rec.Code)
+	}
+}
+
+func TestUserIDOnlyShownToOwnerAndStaff(t *testing.T) {
+	h := newTestRouter(t)
+	owner := newUser(userData.RoleMember)
+	id := createPrayer(t, h, owner, `{"title":"Sabr","description":"A hard year","is_anonymous":true}`)
+
+	tests := []struct {
+		name     string
+		user     *userData.User
+		seesUser bool
+	}{
+		{"anonymous", nil, false},
+		{"other member", newUser(userData.RoleMember), false},
+		{"owner", owner, true},
+		{"moderator", newUser(userData.RoleModerator), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, path := range []string{"/prayers/" + id, "/prayers"} {
+				rec := serve(t, h, tt.user, http.MethodGet, path, "")
+				body := decode(t, rec)
+				if items, ok := body["items"].([]any); ok {
+					body = items[0].(map[string]any)
+				}
+
+				userID, shown := body["user_id"]
+				if shown != tt.seesUser || (shown && userID != owner.ID.Hex()) {
+					t.Errorf("GET %s: user_id = %v, shown %v; want shown %v", path, userID, shown, tt.seesUser)
+				}
+				if body["user_name"] != "" {
+					t.Errorf("GET %s: user_name = %v, want anonymous requests to have none", path, body["user_name"])
+				}
+			}
+		})
+	}
+}
+
+func TestUserIDFilterHidesAnonymousRequests(t *testing.T) {
+	h := newTestRouter(t)
+	owner := newUser(userData.RoleMember)
+	createPrayer(t, h, owner, `{"title":"Named","description":"d"}`)
+	createPrayer(t, h, owner, `{"title":"Anonymous","description":"d","is_anonymous":true}`)
+
+	tests := []struct {
+		name string
+		user *userData.User
+		want int
+	}{
+		{"anonymous", nil, 1},
+		{"other member", newUser(userData.RoleMember), 1},
+		{"owner", owner, 2},
+		{"moderator", newUser(userData.RoleModerator), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, h, tt.user, http.MethodGet, "/prayers?user_id="+owner.ID.Hex(), "")
+			if items := decode(t, rec)["items"].([]any); len(items) != tt.want {
+				t.Errorf("listed %d requests, want %d", len(items), tt.want)
+			}
+		})
+	}
+}
+
+func TestArchiveRequiresLogin(t *testing.T) {
+	h := newTestRouter(t)
+
+	if rec := serve(t, h, nil, http.MethodGet, "/prayers/archived", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous archive: status %d, want 401", rec.Code)
+	}
+	if rec := serve(t, h, newUser(userData.RoleMember), http.MethodGet, "/prayers/archived", ""); rec.Code != http.StatusOK {
+		t.Errorf("member archive: status %d, want 200", rec.Code)
+	}
+}
+
+func TestRecentPrayersLimit(t *testing.T) {
+	h := newTestRouter(t)
+	for range 3 {
+		createPrayer(t, h, nil, `{"title":"Hidayah","description":"d"}`)
+	}
+
+	for _, limit := range []string{"0", "-1", "ten"} {
+		if rec := serve(t, h, nil, http.MethodGet, "/prayers/recent?limit="+limit, ""); rec.Code != http.StatusBadRequest {
+			t.Errorf("limit=%s: status %d, want 400", limit, rec.Code)
+		}
+	}
+
+	rec := serve(t, h, nil, http.MethodGet, "/prayers/recent?limit=2", "")
+	var prayers []map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&prayers); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(prayers) != 2 {
+		t.Errorf("limit=2 returned %d requests", len(prayers))
+	}
+}
+
+func TestAnswerPrayerTestimony(t *testing.T) {
+	h := newTestRouter(t)
+	owner := newUser(userData.RoleMember)
+	id := createPrayer(t, h, owner, `{"title":"Shifa","description":"For my father"}`)
+	answer := "/prayers/" + id + "/answer"
+
+	rec := serve(t, h, owner, http.MethodPost, answer, `{"testimony":"He is home and well"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("answer: status %d, body %s", rec.Code, rec.Body.String())
+	}
+
+	// Answering again without a testimony keeps the one shared
+	rec = serve(t, h, owner, http.MethodPost, answer, "")
+	if body := decode(t, rec); body["testimony"] != "He is home and well" {
+		t.Errorf("testimony after answering again = %v, want it kept", body["testimony"])
+	}
+
+	answered := decode(t, serve(t, h, nil, http.MethodGet, "/prayers/answered", ""))["items"].([]any)
+	if len(answered) != 1 {
+		t.Fatalf("answered feed has %d requests, want 1", len(answered))
+	}
+
+	// A flagged testimony holds the request for review and out of the feed
+	rec = serve(t, h, owner, http.MethodPost, answer, `{"testimony":"Send money to this `+blockedWord+`"}`)
+	if status := decode(t, rec)["moderation_status"]; status != moderation.StatusPendingReview {
+		t.Errorf("moderation_status = %v, want %s", status, moderation.StatusPendingReview)
+	}
+	answered = decode(t, serve(t, h, nil, http.MethodGet, "/prayers/answered", ""))["items"].([]any)
+	if len(answered) != 0 {
+		t.Errorf("answered feed has %d requests, want the held one left out", len(answered))
+	}
+	if rec := serve(t, h, nil, http.MethodGet, "/prayers/"+id, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("held request: status %d, want 404", rec.Code)
+	}
+}
+
+func TestHasPrayedOnlyForVisiblePrayers(t *testing.T) {
+	h := newTestRouter(t)
+	owner := newUser(userData.RoleMember)
+	visible := createPrayer(t, h, owner, `{"title":"Shifa","description":"d"}`)
+	held := createPrayer(t, h, owner, `{"title":"Shifa","description":"Beware this `+blockedWord+`"}`)
+	trashed := createPrayer(t, h, owner, `{"title":"Shifa","description":"d"}`)
+	if rec := serve(t, h, owner, http.MethodDelete, "/prayers/"+trashed, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("delete: status %d", rec.Code)
+	}
+
+	if rec := serve(t, h, nil, http.MethodGet, "/prayers/"+visible+"/prayed", ""); rec.Code != http.StatusOK {
+		t.Errorf("published request: status %d, want 200", rec.Code)
+	}
+	for name, id := range map[string]string{"held": held, "trashed": trashed, "missing": bson.NewObjectID().Hex()} {
+		if rec := serve(t, h, nil, http.MethodGet, "/prayers/"+id+"/prayed", ""); rec.Code != http.StatusNotFound {
+			t.Errorf("%s request: status %d, want 404", name, rec.Code)
+		}
+	}
+	if rec := serve(t, h, owner, http.MethodGet, "/prayers/"+held+"/prayed", ""); rec.Code != http.StatusOK {
+		t.Errorf("owner of a held request: status %d, want 200", rec.Code)
+	}
+}
+
+func TestHeldAnswerAnnouncedOnceApproved(t *testing.T) {
+	h, service, repo := newTestService(t)
+	ctx := context.Background()
+	owner := newUser(userData.RoleMember)
+	id := createPrayer(t, h, owner, `{"title":"Shifa","description":"For my sister"}`)
+
+	answers := func() int {
+		t.Helper()
+		page, err := repo.GetActivities(ctx, []string{data.ActivityPrayerAnswered}, pagination.Params{Limit: pagination.DefaultLimit})
+		if err != nil {
+			t.Fatalf("GetActivities: %v", err)
+		}
+		return len(page.Items)
+	}
+
+	rec := serve(t, h, owner, http.MethodPost, "/prayers/"+id+"/answer", `{"testimony":"Thanks to this `+blockedWord+`"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("answer: status %d, body %s", rec.Code, rec.Body.String())
+	}
+	if n := answers(); n != 0 {
+		t.Fatalf("answer held for review was announced %d times", n)
+	}
+
+	if _, err := service.ModeratePrayerRequest(ctx, id, moderation.StatusPublished); err != nil {
+		t.Fatalf("ModeratePrayerRequest: %v", err)
+	}
+	if _, err := service.ModeratePrayerRequest(ctx, id, moderation.StatusPublished); err != nil {
+		t.Fatalf("ModeratePrayerRequest: %v", err)
+	}
+	if n := answers(); n != 1 {
+		t.Errorf("approved answer was announced %d times, want once", n)
+	}
+}
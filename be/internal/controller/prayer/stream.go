@@ -0,0 +1,114 @@
+package prayer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"prayerreq-backend/internal/apierror"
+	"prayerreq-backend/internal/events"
+)
+
+// heartbeatInterval keeps idle streams open through proxies that time out
+// silent connections
+const heartbeatInterval = 15 * time.Second
+
+// streamRetry tells EventSource clients how long to wait before reconnecting
+const streamRetry = 3 * time.Second
+
+// StreamPrayers handles GET /api/v1/prayers/stream?category=
+// It streams prayer_created, pray_count_increased, comment_added and
+// prayer_answered events as Server-Sent Events. The category parameter may be
+// repeated or comma separated. Clients resume with the Last-Event-ID header
+// (or last_event_id parameter) and replay the events they missed.
+func (s *Service) StreamPrayers(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		apierror.Write(w, r, apierror.Internal(fmt.Errorf("response writer does not support flushing")))
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+	var lastID uint64
+	if lastEventID != "" {
+		id, err := strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			apierror.Write(w, r, apierror.BadRequest(apierror.CodeInvalidParameter, "Last-Event-ID must be a number"))
+			return
+		}
+		lastID = id
+	}
+
+	sub, missed := s.events.Subscribe(lastID, categoryFilter(r))
+	defer s.events.Unsubscribe(sub)
+
+	// Streams outlive the server's write timeout
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+	for _, e := range missed {
+		writeEvent(w, e)
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+		case e, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			writeEvent(w, e)
+			flusher.Flush()
+		}
+	}
+}
+
+// categoryFilter builds an event filter from the category query parameters
+func categoryFilter(r *http.Request) events.Filter {
+	var categories []string
+	for _, value := range r.URL.Query()["category"] {
+		for _, c := range strings.Split(value, ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				categories = append(categories, c)
+			}
+		}
+	}
+	if len(categories) == 0 {
+		return nil
+	}
+
+	return func(e events.Event) bool {
+		return slices.Contains(categories, e.Category)
+	}
+}
+
+// writeEvent writes a single event in the text/event-stream format
+func writeEvent(w http.ResponseWriter, e events.Event) {
+	payload, err := json.Marshal(e.Data)
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, payload)
+}
@@ -0,0 +1,82 @@
+package prayer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"prayerreq-backend/internal/apierror"
+	"prayerreq-backend/internal/auth"
+	"prayerreq-backend/internal/pagination"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// GetTrash handles GET /api/v1/prayers/trash?user_id=&limit=&cursor=
+// Users see their own deleted requests; administrators see everyone's,
+// optionally narrowed down to one user.
+func (s *Service) GetTrash(w http.ResponseWriter, r *http.Request) {
+	user := auth.UserFromContext(r.Context())
+	userID := &user.ID
+
+	if auth.IsAdmin(r.Context()) {
+		userID = nil
+		if value := r.URL.Query().Get("user_id"); value != "" {
+			id, err := bson.ObjectIDFromHex(value)
+			if err != nil {
+				apierror.Write(w, r, invalidParameter("user_id must be a valid ID"))
+				return
+			}
+			userID = &id
+		}
+	}
+
+	page, err := pagination.FromRequest(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	prayers, err := s.repo.GetTrashedPrayerRequests(r.Context(), userID, page)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(shownPage(r.Context(), prayers))
+}
+
+// RestorePrayer handles POST /api/v1/prayers/{id}/restore
+func (s *Service) RestorePrayer(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	prayer, err := s.repo.GetTrashedPrayerRequestByID(r.Context(), id)
+	if err != nil {
+		apierror.Write(w, r, apierror.FromRepository(err, CodePrayerNotFound, "Prayer request not found in the trash"))
+		return
+	}
+
+	if !canManage(r, prayer) {
+		apierror.Write(w, r, apierror.Forbidden(CodeNotOwner, "Only the owner can restore this prayer request"))
+		return
+	}
+
+	if err := s.repo.RestorePrayerRequest(r.Context(), id); err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+	prayer.DeletedAt = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(shown(r.Context(), prayer))
+}
+
+// PurgeTrash permanently deletes the prayer requests that have been in the
+// trash for longer than the retention period, along with the notifications
+// about them, and reports how many
+func (s *Service) PurgeTrash(ctx context.Context, retention time.Duration) (int, error) {
+	return s.repo.PurgePrayerRequests(ctx, time.Now().Add(-retention), s.notifier.DeletePrayerNotifications)
+}
@@ -0,0 +1,110 @@
+package data
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Destinations
+const (
+	DestinationMakkah  = "makkah"
+	DestinationMadinah = "madinah"
+	DestinationAlAqsa  = "al_aqsa"
+)
+
+// Trip types
+const (
+	TypeHajj  = "hajj"
+	TypeUmrah = "umrah"
+)
+
+// Trip request statuses
+const (
+	StatusPending = "pending"
+	StatusPrayed  = "prayed"
+)
+
+// Trip represents a journey a traveller announced so others can ask them
+// for prayers
+type Trip struct {
+	ID           bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID       bson.ObjectID `json:"user_id" bson:"user_id"`
+	UserName     string        `json:"user_name" bson:"user_name"`
+	Destination  string        `json:"destination" bson:"destination"` // "makkah", "madinah", "al_aqsa"
+	Type         string        `json:"type" bson:"type"`               // "hajj", "umrah"
+	Description  string        `json:"description" bson:"description"`
+	StartDate    time.Time     `json:"start_date" bson:"start_date"`
+	EndDate      time.Time     `json:"end_date" bson:"end_date"`
+	RequestCount int           `json:"request_count" bson:"request_count"`
+	PrayedCount  int           `json:"prayed_count" bson:"prayed_count"`
+	CreatedAt    time.Time     `json:"created_at" bson:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at" bson:"updated_at"`
+}
+
+// TripRequest represents a request addressed to a trip. Only the traveller
+// sees it, as an item on their checklist.
+type TripRequest struct {
+	ID          bson.ObjectID  `json:"id" bson:"_id,omitempty"`
+	TripID      bson.ObjectID  `json:"trip_id" bson:"trip_id"`
+	UserID      bson.ObjectID  `json:"-" bson:"user_id"`           // set when sent while logged in
+	RequesterID *bson.ObjectID `json:"user_id,omitempty" bson:"-"` // UserID, only shown to the requester and staff
+	UserName    string         `json:"user_name" bson:"user_name"`
+	IsAnonymous bool           `json:"is_anonymous" bson:"is_anonymous"`
+	Message     string         `json:"message" bson:"message"`
+	Status      string         `json:"status" bson:"status"` // "pending", "prayed"
+	PrayedAt    *time.Time     `json:"prayed_at,omitempty" bson:"prayed_at,omitempty"`
+	PrayedPlace string         `json:"prayed_place,omitempty" bson:"prayed_place,omitempty"` // e.g. "Multazam", "Rawdah"
+	CreatedAt   time.Time      `json:"created_at" bson:"created_at"`
+}
+
+// ShownTo returns a copy of the trip request as a user sees it. Who sent a
+// request is only shown to its requester and staff, so the traveller can't
+// tie an anonymous request to an account.
+func (t *TripRequest) ShownTo(userID bson.ObjectID, staff bool) *TripRequest {
+	shown := *t
+	if !t.UserID.IsZero() && (staff || t.UserID == userID) {
+		requester := t.UserID
+		shown.RequesterID = &requester
+	}
+	return &shown
+}
+
+// TripFilter narrows a listing of trips. Trips that have ended are left
+// out unless IncludePast is set.
+type TripFilter struct {
+	Destination string         `json:"destination" validate:"omitempty,oneof=makkah madinah al_aqsa"`
+	Type        string         `json:"type" validate:"omitempty,oneof=hajj umrah"`
+	UserID      *bson.ObjectID `json:"user_id"`
+	IncludePast bool           `json:"include_past"`
+}
+
+// CreateTripInput represents input for creating a trip
+type CreateTripInput struct {
+	Destination string    `json:"destination" validate:"required,oneof=makkah madinah al_aqsa"`
+	Type        string    `json:"type" validate:"required,oneof=hajj umrah"`
+	Description string    `json:"description" validate:"max=2000"`
+	StartDate   time.Time `json:"start_date" validate:"required"`
+	EndDate     time.Time `json:"end_date" validate:"required"`
+}
+
+// UpdateTripInput represents input for updating a trip
+type UpdateTripInput struct {
+	Destination *string    `json:"destination" validate:"omitempty,oneof=makkah madinah al_aqsa"`
+	Type        *string    `json:"type" validate:"omitempty,oneof=hajj umrah"`
+	Description *string    `json:"description" validate:"omitempty,max=2000"`
+	StartDate   *time.Time `json:"start_date"`
+	EndDate     *time.Time `json:"end_date"`
+}
+
+// CreateTripRequestInput represents input for addressing a request to a trip
+type CreateTripRequestInput struct {
+	Message     string `json:"message" validate:"required,max=1000"`
+	UserName    string `json:"user_name" validate:"max=100"`
+	IsAnonymous bool   `json:"is_anonymous"`
+}
+
+// MarkPrayedInput represents the place a traveller prayed for a request
+type MarkPrayedInput struct {
+	Place string `json:"place" validate:"required,max=100"`
+}
@@ -0,0 +1,27 @@
+package trip
+
+import "prayerreq-backend/internal/apierror"
+
+// Error codes returned by the trip endpoints
+const (
+	CodeTripNotFound        = "trip_not_found"
+	CodeTripRequestNotFound = "trip_request_not_found"
+	CodeNotTraveller        = "not_traveller"
+	CodeTripEnded           = "trip_ended"
+	CodeInvalidDates        = "invalid_dates"
+)
+
+// tripNotFound maps an error from looking up a trip by ID
+func tripNotFound(err error) *apierror.Error {
+	return apierror.FromRepository(err, CodeTripNotFound, "Trip not found")
+}
+
+// tripRequestNotFound maps an error from looking up a trip request by ID
+func tripRequestNotFound(err error) *apierror.Error {
+	return apierror.FromRepository(err, CodeTripRequestNotFound, "Trip request not found")
+}
+
+// invalidParameter reports a query parameter that couldn't be parsed
+func invalidParameter(message string) *apierror.Error {
+	return apierror.BadRequest(apierror.CodeInvalidParameter, message)
+}
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"prayerreq-backend/internal/controller/trip/data"
+	"prayerreq-backend/internal/pagination"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// memoryRepository implements Repository interface in memory.
+// It mirrors the behaviour of mongoRepository: results are ordered the way
+// the Mongo queries sort them, missing documents yield mongo.ErrNoDocuments
+// and malformed IDs fail the same way ObjectIDFromHex does.
+type memoryRepository struct {
+	mu       sync.RWMutex
+	trips    []*data.Trip
+	requests []*data.TripRequest
+}
+
+// NewMemoryRepository creates a new in-memory repository for trips
+func NewMemoryRepository() Repository {
+	return &memoryRepository{}
+}
+
+// CreateTrip creates a new trip
+func (r *memoryRepository) CreateTrip(ctx context.Context, trip *data.Trip) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *trip
+	if stored.ID.IsZero() {
+		stored.ID = bson.NewObjectID()
+	}
+	if r.indexOf(stored.ID) >= 0 {
+		return fmt.Errorf("duplicate key: trip %s already exists", stored.ID.Hex())
+	}
+
+	r.trips = append(r.trips, &stored)
+	return nil
+}
+
+// GetTripByID retrieves a trip by ID
+func (r *memoryRepository) GetTripByID(ctx context.Context, id string) (*data.Trip, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.indexOf(objectID)
+	if i < 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	trip := *r.trips[i]
+	return &trip, nil
+}
+
+// GetTrips retrieves a page of trips matching the filter. Trips that ended
+// before now are left out unless the filter includes past trips.
+func (r *memoryRepository) GetTrips(ctx context.Context, filter data.TripFilter, now time.Time, page pagination.Params) (*pagination.Page[*data.Trip], error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var trips []*data.Trip
+	for _, t := range r.trips {
+		switch {
+		case filter.Destination != "" && t.Destination != filter.Destination,
+			filter.Type != "" && t.Type != filter.Type,
+			filter.UserID != nil && t.UserID != *filter.UserID,
+			!filter.IncludePast && t.EndDate.Before(now):
+			continue
+		}
+		trip := *t
+		trips = append(trips, &trip)
+	}
+
+	return pagination.Slice(trips, page, tripOrder.Desc, tripCursor), nil
+}
+
+// UpdateTrip updates a trip
+func (r *memoryRepository) UpdateTrip(ctx context.Context, id string, trip *data.Trip) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i := r.indexOf(objectID); i >= 0 {
+		stored := *trip
+		stored.ID = objectID
+		r.trips[i] = &stored
+	}
+	return nil
+}
+
+// DeleteTrip deletes a trip and the requests addressed to it
+func (r *memoryRepository) DeleteTrip(ctx context.Context, id string) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests = slices.DeleteFunc(r.requests, func(req *data.TripRequest) bool {
+		return req.TripID == objectID
+	})
+	r.trips = slices.DeleteFunc(r.trips, func(t *data.Trip) bool {
+		return t.ID == objectID
+	})
+	return nil
+}
+
+// CreateTripRequest adds a request to a trip and counts it on the trip
+func (r *memoryRepository) CreateTripRequest(ctx context.Context, req *data.TripRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := copyTripRequest(req)
+	if stored.ID.IsZero() {
+		stored.ID = bson.NewObjectID()
+	}
+	if r.requestIndexOf(stored.TripID, stored.ID) >= 0 {
+		return fmt.Errorf("duplicate key: trip request %s already exists", stored.ID.Hex())
+	}
+
+	r.requests = append(r.requests, stored)
+	if i := r.indexOf(stored.TripID); i >= 0 {
+		r.trips[i].RequestCount++
+	}
+	return nil
+}
+
+// GetTripRequestByID retrieves a request addressed to a trip
+func (r *memoryRepository) GetTripRequestByID(ctx context.Context, tripID, id string) (*data.TripRequest, error) {
+	tripObjectID, err := bson.ObjectIDFromHex(tripID)
+	if err != nil {
+		return nil, err
+	}
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.requestIndexOf(tripObjectID, objectID)
+	if i < 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	return copyTripRequest(r.requests[i]), nil
+}
+
+// GetTripRequests retrieves a page of a trip's requests, optionally limited
+// to one status
+func (r *memoryRepository) GetTripRequests(ctx context.Context, tripID, status string, page pagination.Params) (*pagination.Page[*data.TripRequest], error) {
+	objectID, err := bson.ObjectIDFromHex(tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var requests []*data.TripRequest
+	for _, req := range r.requests {
+		if req.TripID == objectID && (status == "" || req.Status == status) {
+			requests = append(requests, copyTripRequest(req))
+		}
+	}
+
+	return pagination.Slice(requests, page, tripRequestsDesc, tripRequestCursor), nil
+}
+
+// MarkTripRequestPrayed records where the traveller prayed for a request.
+// It reports whether the request was still pending, in which case the
+// trip's prayed count goes up; marking it again just records the new
+// place and time.
+func (r *memoryRepository) MarkTripRequestPrayed(ctx context.Context, tripID, id, place string, prayedAt time.Time) (bool, error) {
+	tripObjectID, err := bson.ObjectIDFromHex(tripID)
+	if err != nil {
+		return false, err
+	}
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.requestIndexOf(tripObjectID, objectID)
+	if i < 0 {
+		return false, mongo.ErrNoDocuments
+	}
+
+	req := r.requests[i]
+	wasPending := req.Status != data.StatusPrayed
+	req.Status = data.StatusPrayed
+	req.PrayedAt = &prayedAt
+	req.PrayedPlace = place
+
+	if wasPending {
+		if j := r.indexOf(tripObjectID); j >= 0 {
+			r.trips[j].PrayedCount++
+		}
+	}
+	return wasPending, nil
+}
+
+// indexOf returns the position of the trip with the given ID, or -1.
+// Callers must hold the lock.
+func (r *memoryRepository) indexOf(id bson.ObjectID) int {
+	return slices.IndexFunc(r.trips, func(t *data.Trip) bool {
+		return t.ID == id
+	})
+}
+
+// requestIndexOf returns the position of the trip request with the given
+// IDs, or -1. Callers must hold the lock.
+func (r *memoryRepository) requestIndexOf(tripID, id bson.ObjectID) int {
+	return slices.IndexFunc(r.requests, func(req *data.TripRequest) bool {
+		return req.TripID == tripID && req.ID == id
+	})
+}
+
+// copyTripRequest returns a deep copy so callers can't mutate stored state
+func copyTripRequest(req *data.TripRequest) *data.TripRequest {
+	c := *req
+	if req.PrayedAt != nil {
+		prayedAt := *req.PrayedAt
+		c.PrayedAt = &prayedAt
+	}
+	return &c
+}
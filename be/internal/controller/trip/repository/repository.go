@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"prayerreq-backend/internal/controller/trip/data"
+	"prayerreq-backend/internal/pagination"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Repository defines the interface for trip data access
+type Repository interface {
+	CreateTrip(ctx context.Context, trip *data.Trip) error
+	GetTripByID(ctx context.Context, id string) (*data.Trip, error)
+	GetTrips(ctx context.Context, filter data.TripFilter, now time.Time, page pagination.Params) (*pagination.Page[*data.Trip], error)
+	UpdateTrip(ctx context.Context, id string, trip *data.Trip) error
+	DeleteTrip(ctx context.Context, id string) error
+	// Trip request methods. Creating and marking requests keeps the trip's
+	// counts up to date.
+	CreateTripRequest(ctx context.Context, req *data.TripRequest) error
+	GetTripRequestByID(ctx context.Context, tripID, id string) (*data.TripRequest, error)
+	GetTripRequests(ctx context.Context, tripID, status string, page pagination.Params) (*pagination.Page[*data.TripRequest], error)
+	MarkTripRequestPrayed(ctx context.Context, tripID, id, place string, prayedAt time.Time) (bool, error)
+}
+
+// tripOrder lists trips by departure, soonest first
+var tripOrder = pagination.Order{TimeField: "start_date", Desc: false}
+
+// tripCursor returns the pagination cursor for a trip
+func tripCursor(trip *data.Trip) pagination.Cursor {
+	return pagination.Cursor{Time: trip.StartDate, ID: trip.ID}
+}
+
+// Trip requests are listed oldest first, in the order they were received
+const tripRequestsDesc = false
+
+// tripRequestCursor returns the pagination cursor for a trip request
+func tripRequestCursor(req *data.TripRequest) pagination.Cursor {
+	return pagination.Cursor{Time: req.CreatedAt, ID: req.ID}
+}
+
+// mongoRepository implements Repository interface using MongoDB
+type mongoRepository struct {
+	collection *mongo.Collection
+	requests   *mongo.Collection
+}
+
+// NewMongoRepository creates a new MongoDB repository for trips
+func NewMongoRepository(db *mongo.Database) Repository {
+	return &mongoRepository{
+		collection: db.Collection("trips"),
+		requests:   db.Collection("trip_requests"),
+	}
+}
+
+// CreateTrip creates a new trip
+func (r *mongoRepository) CreateTrip(ctx context.Context, trip *data.Trip) error {
+	_, err := r.collection.InsertOne(ctx, trip)
+	return err
+}
+
+// GetTripByID retrieves a trip by ID
+func (r *mongoRepository) GetTripByID(ctx context.Context, id string) (*data.Trip, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var trip data.Trip
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&trip)
+	if err != nil {
+		return nil, err
+	}
+
+	return &trip, nil
+}
+
+// GetTrips retrieves a page of trips matching the filter. Trips that ended
+// before now are left out unless the filter includes past trips.
+func (r *mongoRepository) GetTrips(ctx context.Context, filter data.TripFilter, now time.Time, page pagination.Params) (*pagination.Page[*data.Trip], error) {
+	query := bson.M{}
+	if filter.Destination != "" {
+		query["destination"] = filter.Destination
+	}
+	if filter.Type != "" {
+		query["type"] = filter.Type
+	}
+	if filter.UserID != nil {
+		query["user_id"] = *filter.UserID
+	}
+	if !filter.IncludePast {
+		query["end_date"] = bson.M{"$gte": now}
+	}
+
+	return pagination.FindSortedPage(ctx, r.collection, query, page, tripOrder, tripCursor)
+}
+
+// UpdateTrip updates a trip
+func (r *mongoRepository) UpdateTrip(ctx context.Context, id string, trip *data.Trip) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.ReplaceOne(ctx, bson.M{"_id": objectID}, trip)
+	return err
+}
+
+// DeleteTrip deletes a trip and the requests addressed to it. The requests
+// go first so an interrupted delete leaves no orphans.
+func (r *mongoRepository) DeleteTrip(ctx context.Context, id string) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.requests.DeleteMany(ctx, bson.M{"trip_id": objectID}); err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	return err
+}
+
+// CreateTripRequest adds a request to a trip and counts it on the trip
+func (r *mongoRepository) CreateTripRequest(ctx context.Context, req *data.TripRequest) error {
+	if _, err := r.requests.InsertOne(ctx, req); err != nil {
+		return err
+	}
+
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": req.TripID},
+		bson.M{"$inc": bson.M{"request_count": 1}},
+	)
+	return err
+}
+
+// GetTripRequestByID retrieves a request addressed to a trip
+func (r *mongoRepository) GetTripRequestByID(ctx context.Context, tripID, id string) (*data.TripRequest, error) {
+	tripObjectID, err := bson.ObjectIDFromHex(tripID)
+	if err != nil {
+		return nil, err
+	}
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var req data.TripRequest
+	err = r.requests.FindOne(ctx, bson.M{"_id": objectID, "trip_id": tripObjectID}).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+// GetTripRequests retrieves a page of a trip's requests, optionally limited
+// to one status
+func (r *mongoRepository) GetTripRequests(ctx context.Context, tripID, status string, page pagination.Params) (*pagination.Page[*data.TripRequest], error) {
+	objectID, err := bson.ObjectIDFromHex(tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"trip_id": objectID}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	return pagination.FindPage(ctx, r.requests, filter, page, tripRequestsDesc, tripRequestCursor)
+}
+
+// MarkTripRequestPrayed records where the traveller prayed for a request.
+// It reports whether the request was still pending, in which case the
+// trip's prayed count goes up; marking it again just records the new
+// place and time.
+func (r *mongoRepository) MarkTripRequestPrayed(ctx context.Context, tripID, id, place string, prayedAt time.Time) (bool, error) {
+	tripObjectID, err := bson.ObjectIDFromHex(tripID)
+	if err != nil {
+		return false, err
+	}
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	var before data.TripRequest
+	err = r.requests.FindOneAndUpdate(ctx,
+		bson.M{"_id": objectID, "trip_id": tripObjectID},
+		bson.M{"$set": bson.M{
+			"status":       data.StatusPrayed,
+			"prayed_at":    prayedAt,
+			"prayed_place": place,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before),
+	).Decode(&before)
+	if err != nil {
+		return false, err
+	}
+	if before.Status == data.StatusPrayed {
+		return false, nil
+	}
+
+	_, err = r.collection.UpdateOne(ctx,
+		bson.M{"_id": tripObjectID},
+		bson.M{"$inc": bson.M{"prayed_count": 1}},
+	)
+	return err == nil, err
+}
@@ -0,0 +1,38 @@
+package trip
+
+import (
+	"prayerreq-backend/internal/auth"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// NewHTTPHandler creates a new HTTP handler for trips
+func NewHTTPHandler(service *Service) *HTTPHandler {
+	return &HTTPHandler{
+		service: service,
+	}
+}
+
+// HTTPHandler handles HTTP requests for trips
+type HTTPHandler struct {
+	service *Service
+}
+
+// RegisterRoutes registers pilgrimage trip routes
+func (h *HTTPHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/trips", func(r chi.Router) {
+		r.Get("/", h.service.GetTrips)
+		r.With(auth.RequireUser).Post("/", h.service.CreateTrip)
+
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", h.service.GetTripByID)
+			r.With(auth.RequireUser).Put("/", h.service.UpdateTrip)
+			r.With(auth.RequireUser).Delete("/", h.service.DeleteTrip)
+
+			// Requests addressed to the trip; only the traveller sees them
+			r.Post("/requests", h.service.AddTripRequest)
+			r.With(auth.RequireUser).Get("/requests", h.service.GetTripRequests)
+			r.With(auth.RequireUser).Post("/requests/{requestId}/prayed", h.service.MarkPrayed)
+		})
+	})
+}
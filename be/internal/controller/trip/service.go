@@ -0,0 +1,383 @@
+package trip
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"prayerreq-backend/internal/apierror"
+	"prayerreq-backend/internal/auth"
+	"prayerreq-backend/internal/controller/trip/data"
+	"prayerreq-backend/internal/controller/trip/repository"
+	"prayerreq-backend/internal/pagination"
+	"prayerreq-backend/internal/validate"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Service handles pilgrimage trip business logic
+type Service struct {
+	repo repository.Repository
+}
+
+// NewService creates a new trip service
+func NewService(repo repository.Repository) *Service {
+	return &Service{
+		repo: repo,
+	}
+}
+
+// GetTrips handles GET /api/v1/trips?destination=&type=&user_id=&include_past=&limit=&cursor=
+// Trips are listed by departure, soonest first.
+func (s *Service) GetTrips(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseTripFilter(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	page, err := pagination.FromRequest(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	trips, err := s.repo.GetTrips(r.Context(), filter, time.Now(), page)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(trips)
+}
+
+// CreateTrip handles POST /api/v1/trips
+func (s *Service) CreateTrip(w http.ResponseWriter, r *http.Request) {
+	var input data.CreateTripInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		apierror.Write(w, r, apierror.InvalidJSON(err))
+		return
+	}
+	if err := validate.Struct(input); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+	if err := checkDates(input.StartDate, input.EndDate); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	user := auth.UserFromContext(r.Context())
+	trip := &data.Trip{
+		ID:          bson.NewObjectID(),
+		UserID:      user.ID,
+		UserName:    user.Name,
+		Destination: input.Destination,
+		Type:        input.Type,
+		Description: input.Description,
+		StartDate:   input.StartDate,
+		EndDate:     input.EndDate,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if err := s.repo.CreateTrip(r.Context(), trip); err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(trip)
+}
+
+// GetTripByID handles GET /api/v1/trips/{id}
+func (s *Service) GetTripByID(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	trip, err := s.repo.GetTripByID(r.Context(), id)
+	if err != nil {
+		apierror.Write(w, r, tripNotFound(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(trip)
+}
+
+// UpdateTrip handles PUT /api/v1/trips/{id}
+func (s *Service) UpdateTrip(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	trip, err := s.repo.GetTripByID(r.Context(), id)
+	if err != nil {
+		apierror.Write(w, r, tripNotFound(err))
+		return
+	}
+
+	if !isTraveller(r, trip) {
+		apierror.Write(w, r, apierror.Forbidden(CodeNotTraveller, "Only the traveller can update this trip"))
+		return
+	}
+
+	var input data.UpdateTripInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		apierror.Write(w, r, apierror.InvalidJSON(err))
+		return
+	}
+	if err := validate.Struct(input); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	// Update fields if provided
+	if input.Destination != nil {
+		trip.Destination = *input.Destination
+	}
+	if input.Type != nil {
+		trip.Type = *input.Type
+	}
+	if input.Description != nil {
+		trip.Description = *input.Description
+	}
+	if input.StartDate != nil || input.EndDate != nil {
+		if input.StartDate != nil {
+			trip.StartDate = *input.StartDate
+		}
+		if input.EndDate != nil {
+			trip.EndDate = *input.EndDate
+		}
+		if err := checkDates(trip.StartDate, trip.EndDate); err != nil {
+			apierror.Write(w, r, err)
+			return
+		}
+	}
+	trip.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateTrip(r.Context(), id, trip); err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(trip)
+}
+
+// DeleteTrip handles DELETE /api/v1/trips/{id}
+// The requests addressed to the trip are deleted with it.
+func (s *Service) DeleteTrip(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	trip, err := s.repo.GetTripByID(r.Context(), id)
+	if err != nil {
+		apierror.Write(w, r, tripNotFound(err))
+		return
+	}
+
+	if !isTraveller(r, trip) && !auth.IsAdmin(r.Context()) {
+		apierror.Write(w, r, apierror.Forbidden(CodeNotTraveller, "Only the traveller can delete this trip"))
+		return
+	}
+
+	if err := s.repo.DeleteTrip(r.Context(), id); err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddTripRequest handles POST /api/v1/trips/{id}/requests
+// Anyone can ask a traveller for prayers until the trip ends.
+func (s *Service) AddTripRequest(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var input data.CreateTripRequestInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		apierror.Write(w, r, apierror.InvalidJSON(err))
+		return
+	}
+	if err := validate.Struct(input); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	trip, err := s.repo.GetTripByID(r.Context(), id)
+	if err != nil {
+		apierror.Write(w, r, tripNotFound(err))
+		return
+	}
+	if trip.EndDate.Before(time.Now()) {
+		apierror.Write(w, r, apierror.Conflict(CodeTripEnded, "This trip has ended and no longer takes requests"))
+		return
+	}
+
+	req := &data.TripRequest{
+		ID:          bson.NewObjectID(),
+		TripID:      trip.ID,
+		UserName:    input.UserName,
+		IsAnonymous: input.IsAnonymous,
+		Message:     input.Message,
+		Status:      data.StatusPending,
+		CreatedAt:   time.Now(),
+	}
+
+	// Requests made while logged in belong to the user
+	if user := auth.UserFromContext(r.Context()); user != nil {
+		req.UserID = user.ID
+		if req.UserName == "" && !req.IsAnonymous {
+			req.UserName = user.Name
+		}
+	}
+
+	if err := s.repo.CreateTripRequest(r.Context(), req); err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(shown(r.Context(), req))
+}
+
+// GetTripRequests handles GET /api/v1/trips/{id}/requests?status=&limit=&cursor=
+// It is the traveller's checklist, oldest request first.
+func (s *Service) GetTripRequests(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	status := r.URL.Query().Get("status")
+	if status != "" && status != data.StatusPending && status != data.StatusPrayed {
+		apierror.Write(w, r, invalidParameter("status must be pending or prayed"))
+		return
+	}
+
+	page, err := pagination.FromRequest(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	trip, err := s.repo.GetTripByID(r.Context(), id)
+	if err != nil {
+		apierror.Write(w, r, tripNotFound(err))
+		return
+	}
+	if !isTraveller(r, trip) {
+		apierror.Write(w, r, apierror.Forbidden(CodeNotTraveller, "Only the traveller can see the requests for this trip"))
+		return
+	}
+
+	requests, err := s.repo.GetTripRequests(r.Context(), id, status, page)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	for i, req := range requests.Items {
+		requests.Items[i] = shown(r.Context(), req)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(requests)
+}
+
+// MarkPrayed handles POST /api/v1/trips/{id}/requests/{requestId}/prayed
+// The traveller ticks a request off the checklist with the place they
+// prayed at.
+func (s *Service) MarkPrayed(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	requestID := chi.URLParam(r, "requestId")
+
+	var input data.MarkPrayedInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		apierror.Write(w, r, apierror.InvalidJSON(err))
+		return
+	}
+	if err := validate.Struct(input); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	trip, err := s.repo.GetTripByID(r.Context(), id)
+	if err != nil {
+		apierror.Write(w, r, tripNotFound(err))
+		return
+	}
+	if !isTraveller(r, trip) {
+		apierror.Write(w, r, apierror.Forbidden(CodeNotTraveller, "Only the traveller can mark requests as prayed"))
+		return
+	}
+
+	if _, err := s.repo.MarkTripRequestPrayed(r.Context(), id, requestID, input.Place, time.Now()); err != nil {
+		apierror.Write(w, r, tripRequestNotFound(err))
+		return
+	}
+
+	req, err := s.repo.GetTripRequestByID(r.Context(), id, requestID)
+	if err != nil {
+		apierror.Write(w, r, tripRequestNotFound(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(shown(r.Context(), req))
+}
+
+// parseTripFilter reads the filter query parameters of GET /trips
+func parseTripFilter(r *http.Request) (data.TripFilter, error) {
+	query := r.URL.Query()
+	filter := data.TripFilter{
+		Destination: query.Get("destination"),
+		Type:        query.Get("type"),
+	}
+
+	if value := query.Get("user_id"); value != "" {
+		userID, err := bson.ObjectIDFromHex(value)
+		if err != nil {
+			return filter, invalidParameter("user_id must be a valid ID")
+		}
+		filter.UserID = &userID
+	}
+	if value := query.Get("include_past"); value != "" {
+		includePast, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, invalidParameter("include_past must be true or false")
+		}
+		filter.IncludePast = includePast
+	}
+
+	if err := validate.Struct(filter); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// checkDates rejects a trip that ends before it starts or has already ended
+func checkDates(start, end time.Time) error {
+	if end.Before(start) {
+		return apierror.BadRequest(CodeInvalidDates, "end_date must not be before start_date")
+	}
+	if !end.After(time.Now()) {
+		return apierror.BadRequest(CodeInvalidDates, "end_date must be in the future")
+	}
+	return nil
+}
+
+// isTraveller reports whether the authenticated user announced the trip
+func isTraveller(r *http.Request, trip *data.Trip) bool {
+	user := auth.UserFromContext(r.Context())
+	return user != nil && trip.UserID == user.ID
+}
+
+// shown returns the trip request as the caller sees it
+func shown(ctx context.Context, req *data.TripRequest) *data.TripRequest {
+	var userID bson.ObjectID
+	if user := auth.UserFromContext(ctx); user != nil {
+		userID = user.ID
+	}
+	return req.ShownTo(userID, auth.IsStaff(ctx))
+}
@@ -0,0 +1,113 @@
+package trip
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"prayerreq-backend/internal/auth"
+	"prayerreq-backend/internal/controller/trip/repository"
+	userData "prayerreq-backend/internal/controller/user/data"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// newTestRouter serves the trip routes from an in-memory repository
+func newTestRouter(t *testing.T) http.Handler {
+	t.Helper()
+	r := chi.NewRouter()
+	NewHTTPHandler(NewService(repository.NewMemoryRepository())).RegisterRoutes(r)
+	return r
+}
+
+func newUser(name, role string) *userData.User {
+	return &userData.User{ID: bson.NewObjectID(), Name: name, IsActive: true, Role: role}
+}
+
+// serve sends a request as the user, or anonymously when user is nil
+func serve(t *testing.T, h http.Handler, user *userData.User, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if user != nil {
+		req = req.WithContext(auth.WithUser(req.Context(), user))
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// decode reads a JSON response into a map
+func decode(t *testing.T, rec *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	var body map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response %q: %v", rec.Body.String(), err)
+	}
+	return body
+}
+
+// createTrip announces a trip as the traveller and returns its ID
+func createTrip(t *testing.T, h http.Handler, traveller *userData.User) string {
+	t.Helper()
+	start := time.Now().Add(24 * time.Hour)
+	body, _ := json.Marshal(map[string]any{
+		"destination": "makkah",
+		"type":        "umrah",
+		"start_date":  start,
+		"end_date":    start.Add(14 * 24 * time.Hour),
+	})
+
+	rec := serve(t, h, traveller, http.MethodPost, "/trips", string(body))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create trip: status %d, body %s", rec.Code, rec.Body.String())
+	}
+	return decode(t, rec)["id"].(string)
+}
+
+func TestAnonymousTripRequestHidesRequester(t *testing.T) {
+	h := newTestRouter(t)
+	traveller, requester := newUser("Traveller", userData.RoleMember), newUser("Requester", userData.RoleMember)
+	id := createTrip(t, h, traveller)
+
+	rec := serve(t, h, requester, http.MethodPost, "/trips/"+id+"/requests", `{"message":"Make dua for my family","is_anonymous":true}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("add request: status %d, body %s", rec.Code, rec.Body.String())
+	}
+	if body := decode(t, rec); body["user_id"] != requester.ID.Hex() || body["user_name"] != "" {
+		t.Errorf("requester sees user_id %v and user_name %v; want their ID and no name", body["user_id"], body["user_name"])
+	}
+
+	rec = serve(t, h, traveller, http.MethodGet, "/trips/"+id+"/requests", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("checklist: status %d, body %s", rec.Code, rec.Body.String())
+	}
+	items := decode(t, rec)["items"].([]any)
+	if len(items) != 1 {
+		t.Fatalf("checklist has %d requests, want 1", len(items))
+	}
+	item := items[0].(map[string]any)
+	if _, shown := item["user_id"]; shown {
+		t.Errorf("traveller sees user_id %v, want it hidden", item["user_id"])
+	}
+	if item["user_name"] != "" {
+		t.Errorf("traveller sees user_name %v, want anonymous requests to have none", item["user_name"])
+	}
+}
+
+func TestNamedTripRequestUsesAccountName(t *testing.T) {
+	h := newTestRouter(t)
+	id := createTrip(t, h, newUser("Traveller", userData.RoleMember))
+
+	rec := serve(t, h, newUser("Requester", userData.RoleMember), http.MethodPost, "/trips/"+id+"/requests", `{"message":"Make dua for me"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("add request: status %d, body %s", rec.Code, rec.Body.String())
+	}
+	if name := decode(t, rec)["user_name"]; name != "Requester" {
+		t.Errorf("user_name = %v, want the account name", name)
+	}
+}
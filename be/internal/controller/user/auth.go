@@ -0,0 +1,170 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"prayerreq-backend/internal/apierror"
+	"prayerreq-backend/internal/auth"
+	"prayerreq-backend/internal/controller/user/data"
+	"prayerreq-backend/internal/validate"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Register handles POST /api/v1/auth/register
+func (s *Service) Register(w http.ResponseWriter, r *http.Request) {
+	var input data.RegisterInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		apierror.Write(w, r, apierror.InvalidJSON(err))
+		return
+	}
+	if err := validate.Struct(input); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	email := normalizeEmail(input.Email)
+
+	if err := s.checkEmailFree(r.Context(), email, bson.NilObjectID); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	hash, err := auth.HashPassword(input.Password)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	user := &data.User{
+		ID:           bson.NewObjectID(),
+		Email:        email,
+		Name:         input.Name,
+		Avatar:       input.Avatar,
+		PasswordHash: hash,
+		IsActive:     true,
+		Role:         data.RoleMember,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	if err := s.repo.CreateUser(r.Context(), user); err != nil {
+		apierror.Write(w, r, saveError(err))
+		return
+	}
+
+	s.writeTokens(w, r, http.StatusCreated, user)
+}
+
+// Login handles POST /api/v1/auth/login
+func (s *Service) Login(w http.ResponseWriter, r *http.Request) {
+	var input data.LoginInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		apierror.Write(w, r, apierror.InvalidJSON(err))
+		return
+	}
+	if err := validate.Struct(input); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	user, err := s.repo.GetUserByEmail(r.Context(), normalizeEmail(input.Email))
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	// Don't reveal whether the email or the password was wrong
+	if user == nil || user.PasswordHash == "" || !auth.CheckPassword(user.PasswordHash, input.Password) {
+		apierror.Write(w, r, apierror.Unauthorized(CodeInvalidCredentials, "Invalid email or password"))
+		return
+	}
+	if !user.IsActive {
+		apierror.Write(w, r, apierror.Forbidden(CodeAccountDisabled, "Account is disabled"))
+		return
+	}
+
+	s.writeTokens(w, r, http.StatusOK, user)
+}
+
+// Refresh handles POST /api/v1/auth/refresh
+func (s *Service) Refresh(w http.ResponseWriter, r *http.Request) {
+	var input data.RefreshInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		apierror.Write(w, r, apierror.InvalidJSON(err))
+		return
+	}
+	if err := validate.Struct(input); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	claims, err := s.tokens.Verify(input.RefreshToken, auth.RefreshToken)
+	if err != nil {
+		apierror.Write(w, r, auth.TokenError(err))
+		return
+	}
+
+	user, err := s.repo.GetUserByID(r.Context(), claims.Subject)
+	if err != nil || !user.IsActive {
+		apierror.Write(w, r, apierror.Unauthorized(auth.CodeInvalidToken, "The token belongs to an unknown or inactive user"))
+		return
+	}
+
+	s.writeTokens(w, r, http.StatusOK, user)
+}
+
+// Me handles GET /api/v1/auth/me
+func (s *Service) Me(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(auth.UserFromContext(r.Context()))
+}
+
+// writeTokens issues a fresh access and refresh token pair for the user
+func (s *Service) writeTokens(w http.ResponseWriter, r *http.Request, status int, user *data.User) {
+	accessToken, err := s.tokens.Issue(user.ID.Hex(), auth.AccessToken)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	refreshToken, err := s.tokens.Issue(user.ID.Hex(), auth.RefreshToken)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data.AuthResponse{
+		User:         user,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.tokens.AccessTTL().Seconds()),
+	})
+}
+
+// checkEmailFree fails with email_taken when an account other than self
+// already uses the email
+func (s *Service) checkEmailFree(ctx context.Context, email string, self bson.ObjectID) error {
+	existing, err := s.repo.GetUserByEmail(ctx, email)
+	if err == nil && existing.ID != self {
+		return emailTaken()
+	}
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return apierror.Internal(err)
+	}
+	return nil
+}
+
+// normalizeEmail lowercases and trims an email so lookups are case-insensitive
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
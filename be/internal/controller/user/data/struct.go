@@ -6,28 +6,88 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
+// Roles of an account. Moderators review reported and flagged content;
+// administrators can also manage accounts and anyone's prayer requests.
+const (
+	RoleMember    = "member"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
 // User represents a user in the system
 type User struct {
-	ID        bson.ObjectID `json:"id" bson:"_id,omitempty"`
-	Email     string        `json:"email" bson:"email"`
-	Name      string        `json:"name" bson:"name"`
-	Avatar    string        `json:"avatar" bson:"avatar"`
-	IsActive  bool          `json:"is_active" bson:"is_active"`
-	CreatedAt time.Time     `json:"created_at" bson:"created_at"`
-	UpdatedAt time.Time     `json:"updated_at" bson:"updated_at"`
+	ID           bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	Email        string        `json:"email" bson:"email"`
+	Name         string        `json:"name" bson:"name"`
+	Avatar       string        `json:"avatar" bson:"avatar"`
+	PasswordHash string        `json:"-" bson:"password_hash,omitempty"`
+	IsActive     bool          `json:"is_active" bson:"is_active"`
+	Role         string        `json:"role" bson:"role"`
+	CreatedAt    time.Time     `json:"created_at" bson:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at" bson:"updated_at"`
+}
+
+// IsStaff reports whether the user is a moderator or an administrator
+func (u *User) IsStaff() bool {
+	return u.Role == RoleModerator || u.Role == RoleAdmin
+}
+
+// PublicUser is the part of a user anyone can look up
+type PublicUser struct {
+	ID     bson.ObjectID `json:"id"`
+	Name   string        `json:"name"`
+	Avatar string        `json:"avatar"`
+}
+
+// Public returns the part of the user anyone can see
+func (u *User) Public() *PublicUser {
+	return &PublicUser{ID: u.ID, Name: u.Name, Avatar: u.Avatar}
 }
 
 // CreateUserInput represents input for creating a user
 type CreateUserInput struct {
-	Email  string `json:"email" validate:"required,email"`
-	Name   string `json:"name" validate:"required"`
-	Avatar string `json:"avatar"`
+	Email  string `json:"email" validate:"required,email,max=254"`
+	Name   string `json:"name" validate:"required,max=100"`
+	Avatar string `json:"avatar" validate:"max=500"`
 }
 
 // UpdateUserInput represents input for updating a user
 type UpdateUserInput struct {
-	Email    *string `json:"email" validate:"omitempty,email"`
-	Name     *string `json:"name"`
-	Avatar   *string `json:"avatar"`
+	Email    *string `json:"email" validate:"omitempty,email,max=254"`
+	Name     *string `json:"name" validate:"omitempty,required,max=100"`
+	Avatar   *string `json:"avatar" validate:"omitempty,max=500"`
 	IsActive *bool   `json:"is_active"`
 }
+
+// UpdateRoleInput represents input for changing a user's role
+type UpdateRoleInput struct {
+	Role string `json:"role" validate:"required,oneof=member moderator admin"`
+}
+
+// RegisterInput represents input for registering an account
+type RegisterInput struct {
+	Email    string `json:"email" validate:"required,email,max=254"`
+	Name     string `json:"name" validate:"required,max=100"`
+	Password string `json:"password" validate:"required,min=8,maxbytes=72"` // bcrypt rejects passwords over 72 bytes
+	Avatar   string `json:"avatar" validate:"max=500"`
+}
+
+// LoginInput represents input for logging in
+type LoginInput struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// RefreshInput represents input for exchanging a refresh token
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// AuthResponse represents the tokens issued after a successful login
+type AuthResponse struct {
+	User         *User  `json:"user"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"` // seconds until the access token expires
+}
@@ -0,0 +1,37 @@
+package user
+
+import (
+	"prayerreq-backend/internal/apierror"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Error codes returned by the user and authentication endpoints
+const (
+	CodeUserNotFound        = "user_not_found"
+	CodeEmailTaken          = "email_taken"
+	CodeInvalidCredentials  = "invalid_credentials"
+	CodeAccountDisabled     = "account_disabled"
+	CodeCannotChangeOwnRole = "cannot_change_own_role"
+	CodeCannotRemoveSelf    = "cannot_remove_own_account"
+	CodeLastAdmin           = "last_admin"
+)
+
+// userNotFound maps an error from looking up a user by ID
+func userNotFound(err error) *apierror.Error {
+	return apierror.FromRepository(err, CodeUserNotFound, "User not found")
+}
+
+// emailTaken reports that another account already uses an email
+func emailTaken() *apierror.Error {
+	return apierror.Conflict(CodeEmailTaken, "An account with this email already exists")
+}
+
+// saveError maps an error from storing a user. The unique email index
+// catches accounts created with the same email at the same time.
+func saveError(err error) *apierror.Error {
+	if mongo.IsDuplicateKeyError(err) {
+		return emailTaken()
+	}
+	return apierror.Internal(err)
+}
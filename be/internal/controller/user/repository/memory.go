@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"prayerreq-backend/internal/controller/user/data"
+	"prayerreq-backend/internal/pagination"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// memoryRepository implements Repository interface in memory.
+// It mirrors the behaviour of mongoRepository: results are ordered the way
+// the Mongo queries sort them, missing documents yield mongo.ErrNoDocuments
+// and malformed IDs fail the same way ObjectIDFromHex does.
+type memoryRepository struct {
+	mu    sync.RWMutex
+	users []*data.User
+}
+
+// NewMemoryRepository creates a new in-memory repository for users
+func NewMemoryRepository() Repository {
+	return &memoryRepository{}
+}
+
+// CreateUser creates a new user
+func (r *memoryRepository) CreateUser(ctx context.Context, user *data.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *user
+	if stored.ID.IsZero() {
+		stored.ID = bson.NewObjectID()
+	}
+	if r.indexOf(stored.ID) >= 0 {
+		return duplicateKey(fmt.Sprintf("user %s already exists", stored.ID.Hex()))
+	}
+	if r.emailTaken(stored.Email, stored.ID) {
+		return duplicateKey("email " + stored.Email + " is taken")
+	}
+
+	r.users = append(r.users, &stored)
+	return nil
+}
+
+// GetUserByID retrieves a user by ID
+func (r *memoryRepository) GetUserByID(ctx context.Context, id string) (*data.User, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.indexOf(objectID)
+	if i < 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	user := *r.users[i]
+	return &user, nil
+}
+
+// GetUserByEmail retrieves a user by email
+func (r *memoryRepository) GetUserByEmail(ctx context.Context, email string) (*data.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, u := range r.users {
+		if u.Email == email {
+			user := *u
+			return &user, nil
+		}
+	}
+
+	return nil, mongo.ErrNoDocuments
+}
+
+// GetUsers retrieves a page of users
+func (r *memoryRepository) GetUsers(ctx context.Context, page pagination.Params) (*pagination.Page[*data.User], error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var users []*data.User
+	for _, u := range r.users {
+		user := *u
+		users = append(users, &user)
+	}
+
+	return pagination.Slice(users, page, usersDesc, userCursor), nil
+}
+
+// UpdateUser updates a user
+func (r *memoryRepository) UpdateUser(ctx context.Context, id string, user *data.User) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.emailTaken(user.Email, objectID) {
+		return duplicateKey("email " + user.Email + " is taken")
+	}
+	if i := r.indexOf(objectID); i >= 0 {
+		stored := *user
+		stored.ID = objectID
+		r.users[i] = &stored
+	}
+	return nil
+}
+
+// DeleteUser deletes a user
+func (r *memoryRepository) DeleteUser(ctx context.Context, id string) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i := r.indexOf(objectID); i >= 0 {
+		r.users = slices.Delete(r.users, i, i+1)
+	}
+	return nil
+}
+
+// CountActiveUsers counts the active users with a role
+func (r *memoryRepository) CountActiveUsers(ctx context.Context, role string) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := 0
+	for _, u := range r.users {
+		if u.Role == role && u.IsActive {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// indexOf returns the position of the user with the given ID, or -1.
+// Callers must hold the lock.
+func (r *memoryRepository) indexOf(id bson.ObjectID) int {
+	return slices.IndexFunc(r.users, func(u *data.User) bool {
+		return u.ID == id
+	})
+}
+
+// emailTaken reports whether a user other than self has the email, as the
+// unique email index does. Callers must hold the lock.
+func (r *memoryRepository) emailTaken(email string, self bson.ObjectID) bool {
+	return slices.ContainsFunc(r.users, func(u *data.User) bool {
+		return u.Email == email && u.ID != self
+	})
+}
+
+// duplicateKey returns the error Mongo reports for a unique index violation
+func duplicateKey(message string) error {
+	return mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000, Message: "duplicate key: " + message}}}
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"prayerreq-backend/internal/controller/user/data"
+	"prayerreq-backend/internal/pagination"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// seedUsers stores users created a minute apart, oldest first
+func seedUsers(t *testing.T, repo Repository, emails ...string) []*data.User {
+	t.Helper()
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var users []*data.User
+	for i, email := range emails {
+		user := &data.User{
+			ID:        bson.NewObjectID(),
+			Email:     email,
+			Name:      "User",
+			IsActive:  true,
+			Role:      data.RoleMember,
+			CreatedAt: start.Add(time.Duration(i) * time.Minute),
+		}
+		if err := repo.CreateUser(context.Background(), user); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		users = append(users, user)
+	}
+	return users
+}
+
+func TestMemoryGetUsersNewestFirst(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	users := seedUsers(t, repo, "a@example.com", "b@example.com", "c@example.com")
+
+	first, err := repo.GetUsers(ctx, pagination.Params{Limit: 2})
+	if err != nil {
+		t.Fatalf("GetUsers: %v", err)
+	}
+	if len(first.Items) != 2 || first.Items[0].ID != users[2].ID || first.Items[1].ID != users[1].ID {
+		t.Fatalf("first page = %v, want the two newest users", first.Items)
+	}
+	if !first.HasMore {
+		t.Fatal("first page has_more = false, want true")
+	}
+
+	cursor, err := pagination.Decode(first.NextCursor)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	second, err := repo.GetUsers(ctx, pagination.Params{Limit: 2, Cursor: cursor})
+	if err != nil {
+		t.Fatalf("GetUsers: %v", err)
+	}
+	if len(second.Items) != 1 || second.Items[0].ID != users[0].ID || second.HasMore {
+		t.Errorf("second page = %v (has_more %v), want only the oldest user", second.Items, second.HasMore)
+	}
+}
+
+func TestMemoryGetUserErrors(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	seedUsers(t, repo, "a@example.com")
+
+	if _, err := repo.GetUserByID(ctx, bson.NewObjectID().Hex()); !errors.Is(err, mongo.ErrNoDocuments) {
+		t.Errorf("missing ID: err = %v, want mongo.ErrNoDocuments", err)
+	}
+	if _, err := repo.GetUserByID(ctx, "not-an-id"); !errors.Is(err, bson.ErrInvalidHex) {
+		t.Errorf("invalid ID: err = %v, want bson.ErrInvalidHex", err)
+	}
+	if _, err := repo.GetUserByEmail(ctx, "b@example.com"); !errors.Is(err, mongo.ErrNoDocuments) {
+		t.Errorf("missing email: err = %v, want mongo.ErrNoDocuments", err)
+	}
+	if err := repo.UpdateUser(ctx, "not-an-id", &data.User{}); !errors.Is(err, bson.ErrInvalidHex) {
+		t.Errorf("UpdateUser with invalid ID: err = %v, want bson.ErrInvalidHex", err)
+	}
+	if err := repo.DeleteUser(ctx, "not-an-id"); !errors.Is(err, bson.ErrInvalidHex) {
+		t.Errorf("DeleteUser with invalid ID: err = %v, want bson.ErrInvalidHex", err)
+	}
+}
+
+func TestMemoryEmailsAreUnique(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	users := seedUsers(t, repo, "a@example.com", "b@example.com")
+
+	err := repo.CreateUser(ctx, &data.User{Email: "a@example.com"})
+	if !mongo.IsDuplicateKeyError(err) {
+		t.Errorf("CreateUser with a taken email: err = %v, want a duplicate key error", err)
+	}
+
+	taken := *users[1]
+	taken.Email = "a@example.com"
+	if err := repo.UpdateUser(ctx, taken.ID.Hex(), &taken); !mongo.IsDuplicateKeyError(err) {
+		t.Errorf("UpdateUser to a taken email: err = %v, want a duplicate key error", err)
+	}
+
+	// Saving a user with its own email is fine
+	if err := repo.UpdateUser(ctx, users[0].ID.Hex(), users[0]); err != nil {
+		t.Errorf("UpdateUser keeping its email: %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"prayerreq-backend/internal/controller/user/data"
+	"prayerreq-backend/internal/pagination"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -13,9 +14,18 @@ type Repository interface {
 	CreateUser(ctx context.Context, user *data.User) error
 	GetUserByID(ctx context.Context, id string) (*data.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*data.User, error)
-	GetUsers(ctx context.Context) ([]*data.User, error)
+	GetUsers(ctx context.Context, page pagination.Params) (*pagination.Page[*data.User], error)
 	UpdateUser(ctx context.Context, id string, user *data.User) error
 	DeleteUser(ctx context.Context, id string) error
+	CountActiveUsers(ctx context.Context, role string) (int, error)
+}
+
+// Users are listed newest first
+const usersDesc = true
+
+// userCursor returns the pagination cursor for a user
+func userCursor(user *data.User) pagination.Cursor {
+	return pagination.Cursor{Time: user.CreatedAt, ID: user.ID}
 }
 
 // mongoRepository implements Repository interface using MongoDB
@@ -63,24 +73,9 @@ func (r *mongoRepository) GetUserByEmail(ctx context.Context, email string) (*da
 	return &user, nil
 }
 
-// GetUsers retrieves all users
-func (r *mongoRepository) GetUsers(ctx context.Context) ([]*data.User, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var users []*data.User
-	for cursor.Next(ctx) {
-		var user data.User
-		if err := cursor.Decode(&user); err != nil {
-			return nil, err
-		}
-		users = append(users, &user)
-	}
-
-	return users, cursor.Err()
+// GetUsers retrieves a page of users
+func (r *mongoRepository) GetUsers(ctx context.Context, page pagination.Params) (*pagination.Page[*data.User], error) {
+	return pagination.FindPage(ctx, r.collection, bson.M{}, page, usersDesc, userCursor)
 }
 
 // UpdateUser updates a user
@@ -104,3 +99,9 @@ func (r *mongoRepository) DeleteUser(ctx context.Context, id string) error {
 	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
 	return err
 }
+
+// CountActiveUsers counts the active users with a role
+func (r *mongoRepository) CountActiveUsers(ctx context.Context, role string) (int, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"role": role, "is_active": true})
+	return int(count), err
+}
@@ -1,6 +1,9 @@
 package user
 
 import (
+	"prayerreq-backend/internal/apierror"
+	"prayerreq-backend/internal/auth"
+
 	"github.com/go-chi/chi/v5"
 )
 
@@ -16,13 +19,26 @@ type HTTPHandler struct {
 	service *Service
 }
 
-// RegisterRoutes registers user routes
+// RegisterRoutes registers user and authentication routes
 func (h *HTTPHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/auth", func(r chi.Router) {
+		if h.service.features.Registration {
+			r.Post("/register", h.service.Register)
+		} else {
+			r.Post("/register", apierror.FeatureDisabled)
+		}
+		r.Post("/login", h.service.Login)
+		r.Post("/refresh", h.service.Refresh)
+		r.With(auth.RequireUser).Get("/me", h.service.Me)
+	})
+
 	r.Route("/users", func(r chi.Router) {
-		r.Get("/", h.service.GetUsers)
-		r.Post("/", h.service.CreateUser)
+		r.With(auth.RequireStaff).Get("/", h.service.GetUsers)
+		r.With(auth.RequireAdmin).Post("/", h.service.CreateUser)
 		r.Get("/{id}", h.service.GetUserByID)
-		r.Put("/{id}", h.service.UpdateUser)
-		r.Delete("/{id}", h.service.DeleteUser)
+		r.With(auth.RequireUser).Get("/{id}/saved", h.service.GetSavedPrayers)
+		r.With(auth.RequireUser).Put("/{id}", h.service.UpdateUser)
+		r.With(auth.RequireAdmin).Put("/{id}/role", h.service.UpdateRole)
+		r.With(auth.RequireStaff).Delete("/{id}", h.service.DeleteUser)
 	})
 }
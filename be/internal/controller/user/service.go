@@ -1,34 +1,60 @@
 package user
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"time"
 
+	"prayerreq-backend/internal/apierror"
+	"prayerreq-backend/internal/auth"
+	"prayerreq-backend/internal/config"
+	prayerData "prayerreq-backend/internal/controller/prayer/data"
 	"prayerreq-backend/internal/controller/user/data"
 	"prayerreq-backend/internal/controller/user/repository"
+	"prayerreq-backend/internal/pagination"
+	"prayerreq-backend/internal/validate"
 
 	"github.com/go-chi/chi/v5"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
+// SavedPrayers is the subset of the prayer repository that lists the
+// prayer requests a user saved
+type SavedPrayers interface {
+	GetSavedPrayerRequests(ctx context.Context, userID bson.ObjectID, page pagination.Params) (*pagination.Page[*prayerData.SavedPrayerRequest], error)
+}
+
 // Service handles user business logic
 type Service struct {
-	repo repository.Repository
+	repo     repository.Repository
+	tokens   *auth.TokenManager
+	saved    SavedPrayers
+	features config.Features
 }
 
 // NewService creates a new user service
-func NewService(repo repository.Repository) *Service {
+func NewService(repo repository.Repository, tokens *auth.TokenManager, saved SavedPrayers, features config.Features) *Service {
 	return &Service{
-		repo: repo,
+		repo:     repo,
+		tokens:   tokens,
+		saved:    saved,
+		features: features,
 	}
 }
 
-// GetUsers handles GET /api/v1/users
+// GetUsers handles GET /api/v1/users?limit=&cursor=
 func (s *Service) GetUsers(w http.ResponseWriter, r *http.Request) {
-	users, err := s.repo.GetUsers(r.Context())
+	page, err := pagination.FromRequest(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	users, err := s.repo.GetUsers(r.Context(), page)
 	if err != nil {
-		http.Error(w, "Failed to get users: "+err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, r, apierror.Internal(err))
 		return
 	}
 
@@ -37,25 +63,37 @@ func (s *Service) GetUsers(w http.ResponseWriter, r *http.Request) {
 }
 
 // CreateUser handles POST /api/v1/users
+// Only administrators create accounts this way; everyone else registers.
 func (s *Service) CreateUser(w http.ResponseWriter, r *http.Request) {
 	var input data.CreateUserInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		apierror.Write(w, r, apierror.InvalidJSON(err))
+		return
+	}
+	if err := validate.Struct(input); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	email := normalizeEmail(input.Email)
+	if err := s.checkEmailFree(r.Context(), email, bson.NilObjectID); err != nil {
+		apierror.Write(w, r, err)
 		return
 	}
 
 	user := &data.User{
 		ID:        bson.NewObjectID(),
-		Email:     input.Email,
+		Email:     email,
 		Name:      input.Name,
 		Avatar:    input.Avatar,
 		IsActive:  true,
+		Role:      data.RoleMember,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
 	if err := s.repo.CreateUser(r.Context(), user); err != nil {
-		http.Error(w, "Failed to create user: "+err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, r, saveError(err))
 		return
 	}
 
@@ -65,38 +103,94 @@ func (s *Service) CreateUser(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetUserByID handles GET /api/v1/users/{id}
+// Users see their whole account and staff see anyone's; everyone else only
+// sees the public profile.
 func (s *Service) GetUserByID(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	user, err := s.repo.GetUserByID(r.Context(), id)
 	if err != nil {
-		http.Error(w, "User not found: "+err.Error(), http.StatusNotFound)
+		apierror.Write(w, r, userNotFound(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(user)
+	if caller := auth.UserFromContext(r.Context()); (caller != nil && caller.ID == user.ID) || auth.IsStaff(r.Context()) {
+		json.NewEncoder(w).Encode(user)
+		return
+	}
+	json.NewEncoder(w).Encode(user.Public())
+}
+
+// GetSavedPrayers handles GET /api/v1/users/{id}/saved?limit=&cursor=
+// A saved list is private to its user and the administrators.
+func (s *Service) GetSavedPrayers(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	userID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		apierror.Write(w, r, userNotFound(err))
+		return
+	}
+
+	if user := auth.UserFromContext(r.Context()); user.ID != userID && !auth.IsAdmin(r.Context()) {
+		apierror.Write(w, r, apierror.Forbidden(apierror.CodeForbidden, "You can only see your own saved prayer requests"))
+		return
+	}
+
+	page, err := pagination.FromRequest(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	saved, err := s.saved.GetSavedPrayerRequests(r.Context(), userID, page)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal(err))
+		return
+	}
+	for _, item := range saved.Items {
+		item.PrayerRequest = *item.ShownTo(auth.UserFromContext(r.Context()).ID, auth.IsStaff(r.Context()))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
 }
 
 // UpdateUser handles PUT /api/v1/users/{id}
+// Users can edit their own profile; administrators can edit anyone's and are
+// the only ones who can activate or deactivate an account.
 func (s *Service) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	// Get existing user
 	user, err := s.repo.GetUserByID(r.Context(), id)
 	if err != nil {
-		http.Error(w, "User not found: "+err.Error(), http.StatusNotFound)
+		apierror.Write(w, r, userNotFound(err))
+		return
+	}
+
+	isAdmin := auth.IsAdmin(r.Context())
+	if caller := auth.UserFromContext(r.Context()); caller.ID != user.ID && !isAdmin {
+		apierror.Write(w, r, apierror.Forbidden(apierror.CodeForbidden, "You can only edit your own account"))
 		return
 	}
 
 	var input data.UpdateUserInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		apierror.Write(w, r, apierror.InvalidJSON(err))
+		return
+	}
+	if err := validate.Struct(input); err != nil {
+		apierror.Write(w, r, err)
 		return
 	}
 
 	// Update fields if provided
 	if input.Email != nil {
-		user.Email = *input.Email
+		user.Email = normalizeEmail(*input.Email)
+		if err := s.checkEmailFree(r.Context(), user.Email, user.ID); err != nil {
+			apierror.Write(w, r, err)
+			return
+		}
 	}
 	if input.Name != nil {
 		user.Name = *input.Name
@@ -104,13 +198,27 @@ func (s *Service) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	if input.Avatar != nil {
 		user.Avatar = *input.Avatar
 	}
-	if input.IsActive != nil {
+	if input.IsActive != nil && *input.IsActive != user.IsActive {
+		if !isAdmin {
+			apierror.Write(w, r, apierror.Forbidden(apierror.CodeForbidden, "Only administrators can activate or deactivate accounts"))
+			return
+		}
+		if !*input.IsActive {
+			if err := s.checkRemovable(r, user); err != nil {
+				apierror.Write(w, r, err)
+				return
+			}
+		}
 		user.IsActive = *input.IsActive
 	}
 	user.UpdatedAt = time.Now()
 
 	if err := s.repo.UpdateUser(r.Context(), id, user); err != nil {
-		http.Error(w, "Failed to update user: "+err.Error(), http.StatusInternalServerError)
+		if mongo.IsDuplicateKeyError(err) {
+			apierror.Write(w, r, emailTaken())
+			return
+		}
+		apierror.Write(w, r, userNotFound(err))
 		return
 	}
 
@@ -118,14 +226,90 @@ func (s *Service) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(user)
 }
 
+// UpdateRole handles PUT /api/v1/users/{id}/role
+// Administrators can't change their own role, so there is always one left.
+func (s *Service) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	user, err := s.repo.GetUserByID(r.Context(), id)
+	if err != nil {
+		apierror.Write(w, r, userNotFound(err))
+		return
+	}
+
+	var input data.UpdateRoleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		apierror.Write(w, r, apierror.InvalidJSON(err))
+		return
+	}
+	if err := validate.Struct(input); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	if caller := auth.UserFromContext(r.Context()); caller.ID == user.ID {
+		apierror.Write(w, r, apierror.Conflict(CodeCannotChangeOwnRole, "You can't change your own role"))
+		return
+	}
+
+	if user.Role != input.Role {
+		user.Role = input.Role
+		user.UpdatedAt = time.Now()
+		if err := s.repo.UpdateUser(r.Context(), id, user); err != nil {
+			apierror.Write(w, r, userNotFound(err))
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
 // DeleteUser handles DELETE /api/v1/users/{id}
+// Only administrators can delete the accounts of moderators and administrators.
+// Staff can't delete their own account, and the last active administrator
+// can't be deleted.
 func (s *Service) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
+	user, err := s.repo.GetUserByID(r.Context(), id)
+	if err != nil {
+		apierror.Write(w, r, userNotFound(err))
+		return
+	}
+	if user.IsStaff() && !auth.IsAdmin(r.Context()) {
+		apierror.Write(w, r, apierror.Forbidden(apierror.CodeForbidden, "Only administrators can delete staff accounts"))
+		return
+	}
+	if err := s.checkRemovable(r, user); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
 	if err := s.repo.DeleteUser(r.Context(), id); err != nil {
-		http.Error(w, "Failed to delete user: "+err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, r, userNotFound(err))
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// checkRemovable refuses to delete or deactivate the caller's own account or
+// the last active administrator, so the instance always keeps one
+func (s *Service) checkRemovable(r *http.Request, user *data.User) error {
+	if caller := auth.UserFromContext(r.Context()); caller.ID == user.ID {
+		return apierror.Conflict(CodeCannotRemoveSelf, "You can't delete or deactivate your own account")
+	}
+	if user.Role != data.RoleAdmin || !user.IsActive {
+		return nil
+	}
+
+	admins, err := s.repo.CountActiveUsers(r.Context(), data.RoleAdmin)
+	if err != nil {
+		return apierror.Internal(err)
+	}
+	if admins <= 1 {
+		return apierror.Conflict(CodeLastAdmin, "The last active administrator can't be deleted or deactivated")
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"prayerreq-backend/internal/apierror"
+	"prayerreq-backend/internal/auth"
+	"prayerreq-backend/internal/config"
+	prayerRepo "prayerreq-backend/internal/controller/prayer/repository"
+	"prayerreq-backend/internal/controller/user/data"
+	"prayerreq-backend/internal/controller/user/repository"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// newTestRouter serves the user routes from an in-memory repository
+func newTestRouter(t *testing.T) (http.Handler, repository.Repository) {
+	t.Helper()
+	repo := repository.NewMemoryRepository()
+	tokens := auth.NewTokenManager([]byte("test-secret"), time.Hour, time.Hour)
+	service := NewService(repo, tokens, prayerRepo.NewMemoryRepository(), config.Features{Registration: true})
+
+	r := chi.NewRouter()
+	NewHTTPHandler(service).RegisterRoutes(r)
+	return r, repo
+}
+
+// createUser stores a user with the role
+func createUser(t *testing.T, repo repository.Repository, email, role string) *data.User {
+	t.Helper()
+	user := &data.User{
+		ID:        bson.NewObjectID(),
+		Email:     email,
+		Name:      "User",
+		IsActive:  true,
+		Role:      role,
+		CreatedAt: time.Now(),
+	}
+	if err := repo.CreateUser(context.Background(), user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	return user
+}
+
+// serve sends a request as the user, or anonymously when user is nil
+func serve(t *testing.T, h http.Handler, user *data.User, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if user != nil {
+		req = req.WithContext(auth.WithUser(req.Context(), user))
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// decode reads a JSON response into a map
+func decode(t *testing.T, rec *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	var body map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response %q: %v", rec.Body.String(), err)
+	}
+	return body
+}
+
+// errorCode returns the code of an error response
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	apiErr, _ := decode(t, rec)["error"].(map[string]any)
+	code, _ := apiErr["code"].(string)
+	return code
+}
+
+func TestCreateUserRequiresAdmin(t *testing.T) {
+	h, repo := newTestRouter(t)
+	member := createUser(t, repo, "member@example.com", data.RoleMember)
+	moderator := createUser(t, repo, "moderator@example.com", data.RoleModerator)
+	admin := createUser(t, repo, "admin@example.com", data.RoleAdmin)
+	body := `{"email":"new@example.com","name":"New"}`
+
+	if rec := serve(t, h, nil, http.MethodPost, "/users", body); rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous create: status %d, want 401", rec.Code)
+	}
+	for name, user := range map[string]*data.User{"member": member, "moderator": moderator} {
+		if rec := serve(t, h, user, http.MethodPost, "/users", body); rec.Code != http.StatusForbidden {
+			t.Errorf("%s create: status %d, want 403", name, rec.Code)
+		}
+	}
+
+	rec := serve(t, h, admin, http.MethodPost, "/users", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("admin create: status %d, body %s", rec.Code, rec.Body.String())
+	}
+	if role := decode(t, rec)["role"]; role != data.RoleMember {
+		t.Errorf("role = %v, want %s", role, data.RoleMember)
+	}
+
+	rec = serve(t, h, admin, http.MethodPost, "/users", `{"email":"New@Example.com","name":"Again"}`)
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != CodeEmailTaken {
+		t.Errorf("duplicate create: status %d, want 409 %s", rec.Code, CodeEmailTaken)
+	}
+
+	rec = serve(t, h, nil, http.MethodPost, "/auth/register", `{"email":"new@example.com","name":"Owner","password":"password123"}`)
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != CodeEmailTaken {
+		t.Errorf("register with a taken email: status %d, want 409 %s", rec.Code, CodeEmailTaken)
+	}
+}
+
+func TestRegisterPasswordLength(t *testing.T) {
+	h, _ := newTestRouter(t)
+	register := func(email, password string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]string{"email": email, "name": "Amina", "password": password})
+		return serve(t, h, nil, http.MethodPost, "/auth/register", string(body))
+	}
+
+	// 40 Arabic letters are 80 bytes, more than bcrypt takes
+	rec := register("long@example.com", strings.Repeat("س", 40))
+	if rec.Code != http.StatusBadRequest || errorCode(t, rec) != apierror.CodeValidationFailed {
+		t.Errorf("80-byte password: status %d, want 400 %s", rec.Code, apierror.CodeValidationFailed)
+	}
+
+	if rec := register("arabic@example.com", strings.Repeat("س", 36)); rec.Code != http.StatusCreated {
+		t.Errorf("72-byte password: status %d, body %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestGetUserByIDShowsPublicProfile(t *testing.T) {
+	h, repo := newTestRouter(t)
+	user := createUser(t, repo, "user@example.com", data.RoleMember)
+
+	tests := []struct {
+		name      string
+		caller    *data.User
+		seesEmail bool
+	}{
+		{"anonymous", nil, false},
+		{"other member", createUser(t, repo, "other@example.com", data.RoleMember), false},
+		{"self", user, true},
+		{"moderator", createUser(t, repo, "moderator@example.com", data.RoleModerator), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, h, tt.caller, http.MethodGet, "/users/"+user.ID.Hex(), "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d, body %s", rec.Code, rec.Body.String())
+			}
+			body := decode(t, rec)
+			if body["id"] != user.ID.Hex() || body["name"] != user.Name {
+				t.Errorf("profile = %v, want the user's id and name", body)
+			}
+			if _, ok := body["email"]; ok != tt.seesEmail {
+				t.Errorf("email shown %v, want %v", ok, tt.seesEmail)
+			}
+		})
+	}
+
+	if rec := serve(t, h, nil, http.MethodGet, "/users/not-an-id", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid ID: status %d, want 400", rec.Code)
+	}
+}
+
+func TestUpdateUserPermissions(t *testing.T) {
+	h, repo := newTestRouter(t)
+	member := createUser(t, repo, "member@example.com", data.RoleMember)
+	other := createUser(t, repo, "other@example.com", data.RoleMember)
+	admin := createUser(t, repo, "admin@example.com", data.RoleAdmin)
+	path := "/users/" + member.ID.Hex()
+
+	if rec := serve(t, h, other, http.MethodPut, path, `{"name":"Renamed"}`); rec.Code != http.StatusForbidden {
+		t.Errorf("other member update: status %d, want 403", rec.Code)
+	}
+	if rec := serve(t, h, member, http.MethodPut, path, `{"is_active":false}`); rec.Code != http.StatusForbidden {
+		t.Errorf("member deactivating themselves: status %d, want 403", rec.Code)
+	}
+	if rec := serve(t, h, member, http.MethodPut, path, `{"name":" "}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("blank name: status %d, want 400", rec.Code)
+	}
+
+	rec := serve(t, h, member, http.MethodPut, path, `{"email":"other@example.com"}`)
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != CodeEmailTaken {
+		t.Errorf("taking another user's email: status %d, want 409 %s", rec.Code, CodeEmailTaken)
+	}
+	if rec := serve(t, h, member, http.MethodPut, path, `{"email":"Member@example.com","name":"Renamed"}`); rec.Code != http.StatusOK {
+		t.Errorf("self update keeping the email: status %d, body %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serve(t, h, admin, http.MethodPut, path, `{"is_active":false}`)
+	if rec.Code != http.StatusOK || decode(t, rec)["is_active"] != false {
+		t.Errorf("admin deactivating a member: status %d, want 200 and is_active false", rec.Code)
+	}
+}
+
+func TestUpdateRole(t *testing.T) {
+	h, repo := newTestRouter(t)
+	member := createUser(t, repo, "member@example.com", data.RoleMember)
+	moderator := createUser(t, repo, "moderator@example.com", data.RoleModerator)
+	admin := createUser(t, repo, "admin@example.com", data.RoleAdmin)
+	body := `{"role":"moderator"}`
+
+	if rec := serve(t, h, moderator, http.MethodPut, "/users/"+member.ID.Hex()+"/role", body); rec.Code != http.StatusForbidden {
+		t.Errorf("moderator changing a role: status %d, want 403", rec.Code)
+	}
+
+	rec := serve(t, h, admin, http.MethodPut, "/users/"+admin.ID.Hex()+"/role", `{"role":"member"}`)
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != CodeCannotChangeOwnRole {
+		t.Errorf("admin changing their own role: status %d, want 409 %s", rec.Code, CodeCannotChangeOwnRole)
+	}
+
+	rec = serve(t, h, admin, http.MethodPut, "/users/"+member.ID.Hex()+"/role", body)
+	if rec.Code != http.StatusOK || decode(t, rec)["role"] != data.RoleModerator {
+		t.Errorf("admin promoting a member: status %d, want 200 and role moderator", rec.Code)
+	}
+}
+
+func TestDeleteUserPermissions(t *testing.T) {
+	h, repo := newTestRouter(t)
+	member := createUser(t, repo, "member@example.com", data.RoleMember)
+	other := createUser(t, repo, "other@example.com", data.RoleMember)
+	moderator := createUser(t, repo, "moderator@example.com", data.RoleModerator)
+	admin := createUser(t, repo, "admin@example.com", data.RoleAdmin)
+
+	if rec := serve(t, h, other, http.MethodDelete, "/users/"+member.ID.Hex(), ""); rec.Code != http.StatusForbidden {
+		t.Errorf("member deleting an account: status %d, want 403", rec.Code)
+	}
+	if rec := serve(t, h, moderator, http.MethodDelete, "/users/"+admin.ID.Hex(), ""); rec.Code != http.StatusForbidden {
+		t.Errorf("moderator deleting an administrator: status %d, want 403", rec.Code)
+	}
+	if rec := serve(t, h, moderator, http.MethodDelete, "/users/"+member.ID.Hex(), ""); rec.Code != http.StatusNoContent {
+		t.Errorf("moderator deleting a member: status %d, want 204", rec.Code)
+	}
+	if rec := serve(t, h, admin, http.MethodDelete, "/users/"+moderator.ID.Hex(), ""); rec.Code != http.StatusNoContent {
+		t.Errorf("admin deleting a moderator: status %d, want 204", rec.Code)
+	}
+}
+
+func TestCannotRemoveSelfOrLastAdmin(t *testing.T) {
+	h, repo := newTestRouter(t)
+	admin := createUser(t, repo, "admin@example.com", data.RoleAdmin)
+
+	rec := serve(t, h, admin, http.MethodDelete, "/users/"+admin.ID.Hex(), "")
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != CodeCannotRemoveSelf {
+		t.Errorf("admin deleting themselves: status %d, want 409 %s", rec.Code, CodeCannotRemoveSelf)
+	}
+	rec = serve(t, h, admin, http.MethodPut, "/users/"+admin.ID.Hex(), `{"is_active":false}`)
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != CodeCannotRemoveSelf {
+		t.Errorf("admin deactivating themselves: status %d, want 409 %s", rec.Code, CodeCannotRemoveSelf)
+	}
+
+	// A caller whose account is gone leaves a single active administrator
+	stale := &data.User{ID: bson.NewObjectID(), IsActive: true, Role: data.RoleAdmin}
+	rec = serve(t, h, stale, http.MethodPut, "/users/"+admin.ID.Hex(), `{"is_active":false}`)
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != CodeLastAdmin {
+		t.Errorf("deactivating the last admin: status %d, want 409 %s", rec.Code, CodeLastAdmin)
+	}
+	rec = serve(t, h, stale, http.MethodDelete, "/users/"+admin.ID.Hex(), "")
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != CodeLastAdmin {
+		t.Errorf("deleting the last admin: status %d, want 409 %s", rec.Code, CodeLastAdmin)
+	}
+
+	// With another administrator around, one can go
+	other := createUser(t, repo, "other-admin@example.com", data.RoleAdmin)
+	if rec := serve(t, h, admin, http.MethodDelete, "/users/"+other.ID.Hex(), ""); rec.Code != http.StatusNoContent {
+		t.Errorf("deleting another admin: status %d, want 204", rec.Code)
+	}
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// migrationsCollection records which migrations have been applied
+const migrationsCollection = "schema_migrations"
+
+// Migration is a versioned change to the database schema. Up must be safe
+// to run again if it fails halfway, since the version is only recorded
+// once it returns without error.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+}
+
+// MigrationStatus reports whether a migration has been applied
+type MigrationStatus struct {
+	Version     int
+	Description string
+	AppliedAt   *time.Time
+}
+
+// appliedMigration is the document stored in schema_migrations
+type appliedMigration struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+}
+
+// Migrate applies the pending migrations in version order and returns how
+// many were applied
+func (d *DB) Migrate(ctx context.Context) (int, error) {
+	applied, err := d.appliedMigrations(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, m := range sortedMigrations() {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		log.Printf("Applying migration %d: %s", m.Version, m.Description)
+		if err := m.Up(ctx, d.Database); err != nil {
+			return count, fmt.Errorf("migration %d (%s): %w", m.Version, m.Description, err)
+		}
+
+		record := appliedMigration{
+			Version:     m.Version,
+			Description: m.Description,
+			AppliedAt:   time.Now(),
+		}
+		// Another instance may have applied the same migration concurrently;
+		// migrations are idempotent, so the duplicate record is harmless
+		_, err := d.Database.Collection(migrationsCollection).InsertOne(ctx, record)
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return count, fmt.Errorf("recording migration %d: %w", m.Version, err)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// MigrationStatus lists every known migration and when it was applied
+func (d *DB) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := d.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []MigrationStatus
+	for _, m := range sortedMigrations() {
+		status := MigrationStatus{Version: m.Version, Description: m.Description}
+		if record, ok := applied[m.Version]; ok {
+			status.AppliedAt = &record.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// appliedMigrations returns the recorded migrations keyed by version
+func (d *DB) appliedMigrations(ctx context.Context) (map[int]appliedMigration, error) {
+	cursor, err := d.Database.Collection(migrationsCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var records []appliedMigration
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]appliedMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+
+	return applied, nil
+}
+
+// sortedMigrations returns the registered migrations ordered by version
+func sortedMigrations() []Migration {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+	return sorted
+}
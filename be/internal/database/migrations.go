@@ -0,0 +1,385 @@
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// migrations lists every schema change. Append new migrations with the next
+// version number; never edit or renumber one that has shipped.
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "prayer request indexes for listing, filters and text search",
+		Up: createIndexes("prayer_requests",
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName("created_at_id"),
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "category", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName("category_created_at_id"),
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "is_answered", Value: 1}},
+				Options: options.Index().SetName("is_answered"),
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "priority", Value: 1}},
+				Options: options.Index().SetName("priority"),
+			},
+			mongo.IndexModel{
+				Keys: bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}, {Key: "tags", Value: "text"}},
+				Options: options.Index().SetName("text_search").
+					SetWeights(bson.D{{Key: "title", Value: 10}, {Key: "tags", Value: 5}, {Key: "description", Value: 1}}),
+			},
+		),
+	},
+	{
+		Version:     2,
+		Description: "comment listing index",
+		Up: createIndexes("comments", mongo.IndexModel{
+			Keys:    bson.D{{Key: "prayer_request_id", Value: 1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}},
+			Options: options.Index().SetName("prayer_request_id_created_at_id"),
+		}),
+	},
+	{
+		// Guarded concurrent prayers by the same identity until migration 20
+		// moved prayers into reactions
+		Version:     3,
+		Description: "one prayer per identity and request",
+		Up: createIndexes("prayers", mongo.IndexModel{
+			Keys:    bson.D{{Key: "prayer_request_id", Value: 1}, {Key: "identity", Value: 1}},
+			Options: options.Index().SetName("prayer_request_id_identity").SetUnique(true),
+		}),
+	},
+	{
+		Version:     4,
+		Description: "activity feed index",
+		Up: createIndexes("activities", mongo.IndexModel{
+			Keys:    bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("created_at_id"),
+		}),
+	},
+	{
+		Version:     5,
+		Description: "unique user emails and user listing index",
+		Up: createIndexes("users",
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "email", Value: 1}},
+				Options: options.Index().SetName("email").SetUnique(true),
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName("created_at_id"),
+			},
+		),
+	},
+	{
+		Version:     6,
+		Description: "prayer request indexes for listing filters and sort orders",
+		Up: createIndexes("prayer_requests",
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "pray_count", Value: -1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName("pray_count_created_at_id"),
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "updated_at", Value: -1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName("updated_at_id"),
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "tags", Value: 1}},
+				Options: options.Index().SetName("tags"),
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+				Options: options.Index().SetName("user_id_created_at"),
+			},
+		),
+	},
+	{
+		Version:     7,
+		Description: "trash listing and purge indexes",
+		Up: createIndexes("prayer_requests",
+			mongo.IndexModel{
+				Keys: bson.D{{Key: "deleted_at", Value: -1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName("deleted_at_id").
+					SetPartialFilterExpression(bson.M{"deleted_at": bson.M{"$exists": true}}),
+			},
+			mongo.IndexModel{
+				Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "deleted_at", Value: -1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName("user_id_deleted_at_id").
+					SetPartialFilterExpression(bson.M{"deleted_at": bson.M{"$exists": true}}),
+			},
+		),
+	},
+	{
+		Version:     8,
+		Description: "expiry and archive indexes",
+		Up: createIndexes("prayer_requests",
+			mongo.IndexModel{
+				Keys: bson.D{{Key: "expires_at", Value: 1}},
+				Options: options.Index().SetName("expires_at").
+					SetPartialFilterExpression(bson.M{"expires_at": bson.M{"$exists": true}}),
+			},
+			mongo.IndexModel{
+				Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "archived_at", Value: -1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName("user_id_archived_at_id").
+					SetPartialFilterExpression(bson.M{"archived_at": bson.M{"$exists": true}}),
+			},
+		),
+	},
+	{
+		Version:     9,
+		Description: "trips indexes",
+		Up: createIndexes("trips",
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "start_date", Value: 1}, {Key: "_id", Value: 1}},
+				Options: options.Index().SetName("start_date_id"),
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "start_date", Value: 1}},
+				Options: options.Index().SetName("user_id_start_date"),
+			},
+		),
+	},
+	{
+		Version:     10,
+		Description: "trip requests indexes",
+		Up: createIndexes("trip_requests",
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "trip_id", Value: 1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}},
+				Options: options.Index().SetName("trip_id_created_at_id"),
+			},
+		),
+	},
+	{
+		Version:     11,
+		Description: "prayer request location index",
+		Up: createIndexes("prayer_requests",
+			mongo.IndexModel{
+				Keys: bson.D{{Key: "location.holy_site", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName("location_holy_site_created_at_id").
+					SetPartialFilterExpression(bson.M{"location.holy_site": bson.M{"$exists": true}}),
+			},
+		),
+	},
+	{
+		Version:     12,
+		Description: "saved prayers indexes",
+		Up: createIndexes("saved_prayers",
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "prayer_request_id", Value: 1}, {Key: "user_id", Value: 1}},
+				Options: options.Index().SetName("prayer_request_id_user_id").SetUnique(true),
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName("user_id_created_at_id"),
+			},
+		),
+	},
+	{
+		Version:     13,
+		Description: "backfill answered_at and index the answered feed",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			// Requests answered before answered_at existed use their last update
+			_, err := db.Collection("prayer_requests").UpdateMany(ctx,
+				bson.M{"is_answered": true, "answered_at": bson.M{"$exists": false}},
+				bson.A{bson.M{"$set": bson.M{"answered_at": "$updated_at"}}},
+			)
+			if err != nil {
+				return err
+			}
+
+			return createIndexes("prayer_requests",
+				mongo.IndexModel{
+					Keys: bson.D{{Key: "answered_at", Value: -1}, {Key: "_id", Value: -1}},
+					Options: options.Index().SetName("answered_at_id").
+						SetPartialFilterExpression(bson.M{"answered_at": bson.M{"$exists": true}}),
+				},
+				mongo.IndexModel{
+					Keys: bson.D{{Key: "category", Value: 1}, {Key: "answered_at", Value: -1}, {Key: "_id", Value: -1}},
+					Options: options.Index().SetName("category_answered_at_id").
+						SetPartialFilterExpression(bson.M{"answered_at": bson.M{"$exists": true}}),
+				},
+			)(ctx, db)
+		},
+	},
+	{
+		Version:     14,
+		Description: "notifications indexes",
+		Up: createIndexes("notifications",
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "identity", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName("identity_created_at_id"),
+			},
+		),
+	},
+	{
+		Version:     15,
+		Description: "publish existing content and index the moderation queues",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			// Everything posted before moderation existed stays public
+			for _, collection := range []string{"prayer_requests", "comments"} {
+				_, err := db.Collection(collection).UpdateMany(ctx,
+					bson.M{"moderation_status": bson.M{"$exists": false}},
+					bson.A{bson.M{"$set": bson.M{
+						"moderation_status": "published",
+						"report_count":      0,
+						"published_at":      "$created_at",
+					}}},
+				)
+				if err != nil {
+					return err
+				}
+
+				err = createIndexes(collection,
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "moderation_status", Value: 1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}},
+						Options: options.Index().SetName("moderation_status_created_at_id"),
+					},
+				)(ctx, db)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     16,
+		Description: "reports indexes",
+		Up: createIndexes("reports",
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "identity", Value: 1}},
+				Options: options.Index().SetName("target_type_target_id_identity").SetUnique(true),
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName("target_type_target_id_created_at_id"),
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "prayer_request_id", Value: 1}},
+				Options: options.Index().SetName("prayer_request_id"),
+			},
+		),
+	},
+	{
+		Version:     17,
+		Description: "give existing users the member role",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("users").UpdateMany(ctx,
+				bson.M{"role": bson.M{"$exists": false}},
+				bson.M{"$set": bson.M{"role": "member"}},
+			)
+			return err
+		},
+	},
+	{
+		Version:     18,
+		Description: "comment thread indexes",
+		Up: createIndexes("comments",
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "prayer_request_id", Value: 1}, {Key: "thread_id", Value: 1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}},
+				Options: options.Index().SetName("prayer_request_id_thread_id_created_at_id"),
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "thread_id", Value: 1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}},
+				Options: options.Index().SetName("thread_id_created_at_id"),
+			},
+		),
+	},
+	{
+		Version:     19,
+		Description: "count the comments on each prayer request",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			// Published comments that weren't deleted, as the repository counts them
+			cursor, err := db.Collection("prayer_requests").Aggregate(ctx, bson.A{
+				bson.M{"$lookup": bson.M{
+					"from": "comments",
+					"let":  bson.M{"id": "$_id"},
+					"pipeline": bson.A{
+						bson.M{"$match": bson.M{
+							"$expr":             bson.M{"$eq": bson.A{"$prayer_request_id", "$$id"}},
+							"deleted_at":        nil,
+							"moderation_status": "published",
+						}},
+						bson.M{"$count": "n"},
+					},
+					"as": "counted",
+				}},
+				bson.M{"$project": bson.M{
+					"comment_count": bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{"$counted.n", 0}}, 0}},
+				}},
+				bson.M{"$merge": bson.M{"into": "prayer_requests", "on": "_id", "whenMatched": "merge", "whenNotMatched": "discard"}},
+			})
+			if err != nil {
+				return err
+			}
+			return cursor.Close(ctx)
+		},
+	},
+	{
+		Version:     20,
+		Description: "move prayers into reactions and count reactions by type",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			// Every recorded prayer becomes a "pray" reaction. Rerunning after
+			// an interruption keeps the reactions already copied.
+			cursor, err := db.Collection("prayers").Aggregate(ctx, bson.A{
+				bson.M{"$project": bson.M{
+					"prayer_request_id": 1,
+					"type":              bson.M{"$literal": "pray"},
+					"user_id":           1,
+					"user_name":         1,
+					"identity":          1,
+					"created_at":        1,
+				}},
+				bson.M{"$merge": bson.M{"into": "reactions", "on": "_id", "whenMatched": "keepExisting", "whenNotMatched": "insert"}},
+			})
+			if err != nil {
+				return err
+			}
+			if err := cursor.Close(ctx); err != nil {
+				return err
+			}
+
+			err = createIndexes("reactions",
+				// Also guards AddReaction against concurrent upserts
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "prayer_request_id", Value: 1}, {Key: "type", Value: 1}, {Key: "identity", Value: 1}},
+					Options: options.Index().SetName("prayer_request_id_type_identity").SetUnique(true),
+				},
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "prayer_request_id", Value: 1}, {Key: "identity", Value: 1}},
+					Options: options.Index().SetName("prayer_request_id_identity"),
+				},
+			)(ctx, db)
+			if err != nil {
+				return err
+			}
+
+			_, err = db.Collection("prayer_requests").UpdateMany(ctx,
+				bson.M{"reaction_counts": bson.M{"$exists": false}},
+				bson.A{bson.M{"$set": bson.M{"reaction_counts": bson.M{"pray": "$pray_count"}}}},
+			)
+			if err != nil {
+				return err
+			}
+
+			return db.Collection("prayers").Drop(ctx)
+		},
+	},
+}
+
+// createIndexes returns a migration step that creates the indexes on a
+// collection. Creating an index that already exists with the same options
+// is a no-op, so the step can be rerun safely.
+func createIndexes(collection string, models ...mongo.IndexModel) func(context.Context, *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection(collection).Indexes().CreateMany(ctx, models)
+		return err
+	}
+}
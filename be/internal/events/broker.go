@@ -0,0 +1,157 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// subscriberBuffer is how many events may queue up for a subscriber before
+// it is considered too slow and disconnected. Disconnected clients resume
+// from the history using their last event ID.
+const subscriberBuffer = 64
+
+// Event represents a message published to subscribers
+type Event struct {
+	ID        uint64
+	Type      string
+	Category  string
+	Data      any
+	CreatedAt time.Time
+}
+
+// Filter selects the events a subscriber receives. A nil filter matches everything.
+type Filter func(Event) bool
+
+// Subscription receives published events until it is closed
+type Subscription struct {
+	events chan Event
+	filter Filter
+}
+
+// Events returns the channel events are delivered on. It is closed when the
+// subscription ends, either by Unsubscribe, by the broker shutting down or
+// because the subscriber fell too far behind.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// matches reports whether the subscription wants the event
+func (s *Subscription) matches(e Event) bool {
+	return s.filter == nil || s.filter(e)
+}
+
+// Broker fans out events to in-process subscribers and keeps a bounded
+// history so reconnecting subscribers can catch up on what they missed
+type Broker struct {
+	mu          sync.Mutex
+	nextID      uint64
+	history     []Event
+	historySize int
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+// NewBroker creates a new broker remembering up to historySize events
+func NewBroker(historySize int) *Broker {
+	return &Broker{
+		nextID:      1,
+		historySize: historySize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish sends an event to every matching subscriber
+func (b *Broker) Publish(eventType, category string, data any) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	e := Event{
+		ID:        b.nextID,
+		Type:      eventType,
+		Category:  category,
+		Data:      data,
+		CreatedAt: time.Now(),
+	}
+	b.nextID++
+
+	b.history = append(b.history, e)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for sub := range b.subscribers {
+		if !sub.matches(e) {
+			continue
+		}
+		select {
+		case sub.events <- e:
+		default:
+			// Too slow; drop it so it reconnects and resumes from history
+			b.remove(sub)
+		}
+	}
+}
+
+// Subscribe registers a new subscriber. When lastEventID is non-zero, the
+// matching events published after it that are still in the history are
+// returned so the subscriber can replay them before reading Events.
+func (b *Broker) Subscribe(lastEventID uint64, filter Filter) (*Subscription, []Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &Subscription{
+		events: make(chan Event, subscriberBuffer),
+		filter: filter,
+	}
+	if b.closed {
+		close(sub.events)
+		return sub, nil
+	}
+	b.subscribers[sub] = struct{}{}
+
+	// IDs restart with the process, so an ID from the future is stale
+	if lastEventID == 0 || lastEventID >= b.nextID {
+		return sub, nil
+	}
+
+	var missed []Event
+	for _, e := range b.history {
+		if e.ID > lastEventID && sub.matches(e) {
+			missed = append(missed, e)
+		}
+	}
+
+	return sub, missed
+}
+
+// Unsubscribe removes a subscriber and closes its channel
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remove(sub)
+}
+
+// Close disconnects every subscriber and stops accepting events. It is
+// used during shutdown so streaming responses finish promptly.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		b.remove(sub)
+	}
+}
+
+// remove closes a subscription. Callers must hold the lock.
+func (b *Broker) remove(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
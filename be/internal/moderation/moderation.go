@@ -0,0 +1,143 @@
+package moderation
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"prayerreq-backend/internal/config"
+)
+
+// Moderation statuses of prayer requests and comments. Only published
+// content is listed publicly.
+const (
+	StatusPublished     = "published"
+	StatusPendingReview = "pending_review"
+	StatusRejected      = "rejected"
+)
+
+// Reasons content is held for review
+const (
+	ReasonWordlist    = "blocked_word"
+	ReasonLinks       = "too_many_links"
+	ReasonRepetition  = "repetition"
+	ReasonReported    = "reported"
+	ReasonFilterError = "filter_error"
+)
+
+// Verdict is the outcome of screening a piece of content
+type Verdict struct {
+	Flagged bool
+	Reasons []string
+}
+
+// Filter screens user-submitted text. Implementations must be safe for
+// concurrent use.
+type Filter interface {
+	Check(ctx context.Context, texts ...string) (Verdict, error)
+}
+
+// Chain runs several filters, flagging content that any of them flags
+type Chain []Filter
+
+// Check runs every filter in turn and merges their reasons
+func (c Chain) Check(ctx context.Context, texts ...string) (Verdict, error) {
+	var verdict Verdict
+	for _, filter := range c {
+		v, err := filter.Check(ctx, texts...)
+		if err != nil {
+			return Verdict{}, err
+		}
+		if v.Flagged {
+			verdict.Flagged = true
+			verdict.Reasons = append(verdict.Reasons, v.Reasons...)
+		}
+	}
+	return verdict, nil
+}
+
+// Moderator decides the moderation status of new content, and when reports
+// send published content back for review
+type Moderator struct {
+	filter          Filter
+	reportThreshold int
+}
+
+// NewModerator creates a moderator. A nil filter publishes everything, and
+// a report threshold of 0 never holds reported content.
+func NewModerator(filter Filter, reportThreshold int) *Moderator {
+	return &Moderator{
+		filter:          filter,
+		reportThreshold: reportThreshold,
+	}
+}
+
+// New creates the moderator described by the configuration, reading the
+// wordlist file if there is one
+func New(cfg config.Moderation) (*Moderator, error) {
+	if !cfg.Filter {
+		return NewModerator(nil, cfg.ReportThreshold), nil
+	}
+
+	words := cfg.Wordlist
+	if cfg.WordlistFile != "" {
+		fileWords, err := readWordlist(cfg.WordlistFile)
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, fileWords...)
+	}
+
+	return NewModerator(NewWordlistFilter(words, cfg.MaxLinks), cfg.ReportThreshold), nil
+}
+
+// Screen returns the status new content starts in, with the reasons when it
+// is held for review. Content is held if the filter fails, so an outage
+// doesn't let everything through.
+func (m *Moderator) Screen(ctx context.Context, texts ...string) (string, []string) {
+	if m.filter == nil {
+		return StatusPublished, nil
+	}
+
+	verdict, err := m.filter.Check(ctx, texts...)
+	if err != nil {
+		log.Printf("Moderation filter failed, holding content for review: %v", err)
+		return StatusPendingReview, []string{ReasonFilterError}
+	}
+	if verdict.Flagged {
+		return StatusPendingReview, verdict.Reasons
+	}
+	return StatusPublished, nil
+}
+
+// HoldOnReports reports whether content reported by this many distinct
+// identities should be held for review
+func (m *Moderator) HoldOnReports(reports int) bool {
+	return m.reportThreshold > 0 && reports >= m.reportThreshold
+}
+
+// readWordlist reads one entry per line, skipping blank lines and # comments
+func readWordlist(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open moderation wordlist: %w", err)
+	}
+	defer f.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			words = append(words, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read moderation wordlist: %w", err)
+	}
+
+	return words, nil
+}
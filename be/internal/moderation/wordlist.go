@@ -0,0 +1,90 @@
+package moderation
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// maxRepeatedChars is the longest run of one character allowed, such as
+// "!!!!!!!!!!" or "aaaaaaaaaa"
+const maxRepeatedChars = 9
+
+// linkPattern matches URLs and www. addresses
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// WordlistFilter flags content that contains a listed word or phrase, more
+// links than allowed or long runs of a repeated character
+type WordlistFilter struct {
+	entries  [][]string // each entry split into lower-cased words
+	maxLinks int
+}
+
+// NewWordlistFilter creates a filter for the given words and phrases.
+// Entries match whole words regardless of case, so "spam" doesn't match
+// "spammy".
+func NewWordlistFilter(words []string, maxLinks int) *WordlistFilter {
+	f := &WordlistFilter{maxLinks: maxLinks}
+	for _, word := range words {
+		if entry := splitWords(word); len(entry) > 0 {
+			f.entries = append(f.entries, entry)
+		}
+	}
+	return f
+}
+
+// Check screens the texts together
+func (f *WordlistFilter) Check(ctx context.Context, texts ...string) (Verdict, error) {
+	text := strings.Join(texts, "\n")
+
+	var reasons []string
+	if f.containsEntry(splitWords(text)) {
+		reasons = append(reasons, ReasonWordlist)
+	}
+	if len(linkPattern.FindAllStringIndex(text, -1)) > f.maxLinks {
+		reasons = append(reasons, ReasonLinks)
+	}
+	if longestRun(text) > maxRepeatedChars {
+		reasons = append(reasons, ReasonRepetition)
+	}
+
+	return Verdict{Flagged: len(reasons) > 0, Reasons: reasons}, nil
+}
+
+// containsEntry reports whether any entry appears as consecutive words
+func (f *WordlistFilter) containsEntry(words []string) bool {
+	for _, entry := range f.entries {
+		for i := 0; i+len(entry) <= len(words); i++ {
+			if slices.Equal(words[i:i+len(entry)], entry) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitWords splits text into lower-cased words of letters and digits
+func splitWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// longestRun returns the length of the longest run of one character,
+// ignoring whitespace
+func longestRun(text string) int {
+	longest, run := 0, 0
+	var prev rune
+	for _, r := range text {
+		if r == prev && !unicode.IsSpace(r) {
+			run++
+		} else {
+			run = 1
+		}
+		prev = r
+		longest = max(longest, run)
+	}
+	return longest
+}
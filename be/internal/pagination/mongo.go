@@ -0,0 +1,123 @@
+package pagination
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Order names the document fields a listing is sorted by. Documents are
+// ordered by ScoreField if set, then by TimeField and then by _id, all in
+// the same direction. The cursor's Score and Time hold the values of these
+// fields for the last document of a page.
+type Order struct {
+	ScoreField string
+	TimeField  string
+	Desc       bool
+}
+
+// CreatedAt orders documents by creation time
+func CreatedAt(desc bool) Order {
+	return Order{TimeField: "created_at", Desc: desc}
+}
+
+// Filter narrows filter down to the documents that follow the cursor
+func (p Params) Filter(filter bson.M, order Order) bson.M {
+	if p.Cursor == nil {
+		return filter
+	}
+
+	op := "$gt"
+	if order.Desc {
+		op = "$lt"
+	}
+	c := p.Cursor
+	after := []bson.M{
+		{order.TimeField: bson.M{op: c.Time}},
+		{order.TimeField: c.Time, "_id": bson.M{op: c.ID}},
+	}
+	if order.ScoreField != "" {
+		for _, cond := range after {
+			cond[order.ScoreField] = c.Score
+		}
+		after = append([]bson.M{{order.ScoreField: bson.M{op: c.Score}}}, after...)
+	}
+
+	if len(filter) == 0 {
+		return bson.M{"$or": after}
+	}
+	return bson.M{"$and": []bson.M{filter, {"$or": after}}}
+}
+
+// FindOptions returns the sort and limit for a page query. One extra
+// document is requested so the page can tell whether more follow.
+func (p Params) FindOptions(order Order) *options.FindOptionsBuilder {
+	direction := 1
+	if order.Desc {
+		direction = -1
+	}
+
+	var sort bson.D
+	if order.ScoreField != "" {
+		sort = append(sort, bson.E{Key: order.ScoreField, Value: direction})
+	}
+	sort = append(sort, bson.E{Key: order.TimeField, Value: direction}, bson.E{Key: "_id", Value: direction})
+
+	return options.Find().
+		SetSort(sort).
+		SetLimit(int64(p.Limit + 1))
+}
+
+// FindPage runs a paginated find against the collection, ordered by creation time
+func FindPage[T any](ctx context.Context, collection *mongo.Collection, filter bson.M, p Params, desc bool, cursorOf func(*T) Cursor) (*Page[*T], error) {
+	return FindSortedPage(ctx, collection, filter, p, CreatedAt(desc), cursorOf)
+}
+
+// FindSortedPage runs a paginated find against the collection in the given order
+func FindSortedPage[T any](ctx context.Context, collection *mongo.Collection, filter bson.M, p Params, order Order, cursorOf func(*T) Cursor) (*Page[*T], error) {
+	cursor, err := collection.Find(ctx, p.Filter(filter, order), p.FindOptions(order))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []*T
+	for cursor.Next(ctx) {
+		var item T
+		if err := cursor.Decode(&item); err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return NewPage(items, p.Limit, cursorOf), nil
+}
+
+// AggregatePage runs a pipeline that already filters, sorts and limits to
+// p.Limit+1 documents, and builds a page from the result
+func AggregatePage[T any](ctx context.Context, collection *mongo.Collection, pipeline bson.A, p Params, cursorOf func(*T) Cursor) (*Page[*T], error) {
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []*T
+	for cursor.Next(ctx) {
+		var item T
+		if err := cursor.Decode(&item); err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return NewPage(items, p.Limit, cursorOf), nil
+}
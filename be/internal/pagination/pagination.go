@@ -0,0 +1,163 @@
+package pagination
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"prayerreq-backend/internal/apierror"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	// DefaultLimit is the page size used when the caller doesn't ask for one
+	DefaultLimit = 20
+	// MaxLimit is the largest page size a caller may request
+	MaxLimit = 100
+)
+
+var (
+	// ErrInvalidCursor is returned when a cursor can't be decoded
+	ErrInvalidCursor = apierror.BadRequest("invalid_cursor", "The cursor is invalid")
+	// ErrInvalidLimit is returned when the limit is not a positive integer
+	ErrInvalidLimit = apierror.BadRequest("invalid_limit", "The limit must be a positive integer")
+)
+
+// Params represents the pagination parameters of a list request
+type Params struct {
+	Limit  int
+	Cursor *Cursor
+}
+
+// Cursor marks the position of the last item of a page. Items are ordered
+// by a time, usually created_at, and then by _id, so the pair is unique and
+// stable. Ranked listings such as search or sorting by pray count order by
+// Score first. Sort names the ordering the cursor was issued for, so a
+// cursor can't be replayed against a different one.
+type Cursor struct {
+	Sort  string        `json:"o,omitempty"`
+	Score float64       `json:"s,omitempty"`
+	Time  time.Time     `json:"t"`
+	ID    bson.ObjectID `json:"id"`
+}
+
+// Page represents a single page of a list response
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// FromRequest parses the limit and cursor query parameters
+func FromRequest(r *http.Request) (Params, error) {
+	params := Params{Limit: DefaultLimit}
+	query := r.URL.Query()
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			return Params{}, ErrInvalidLimit
+		}
+		params.Limit = min(limit, MaxLimit)
+	}
+
+	if cursorStr := query.Get("cursor"); cursorStr != "" {
+		cursor, err := Decode(cursorStr)
+		if err != nil {
+			return Params{}, err
+		}
+		params.Cursor = cursor
+	}
+
+	return params, nil
+}
+
+// Encode returns the opaque string form of the cursor
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Decode parses a cursor produced by Encode
+func Decode(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+
+	return &c, nil
+}
+
+// Follows reports whether an item with the given sort key comes after the
+// cursor in the requested order
+func (c *Cursor) Follows(key Cursor, desc bool) bool {
+	if c == nil {
+		return true
+	}
+	if desc {
+		return Less(key, *c)
+	}
+	return Less(*c, key)
+}
+
+// Less orders two sort keys by score, then time and then _id
+func Less(a, b Cursor) bool {
+	if a.Score != b.Score {
+		return a.Score < b.Score
+	}
+	if !a.Time.Equal(b.Time) {
+		return a.Time.Before(b.Time)
+	}
+	return bytes.Compare(a.ID[:], b.ID[:]) < 0
+}
+
+// NewPage builds a page from up to limit+1 items. The extra item, if
+// present, is only used to tell whether another page exists.
+func NewPage[T any](items []T, limit int, cursorOf func(T) Cursor) *Page[T] {
+	page := &Page[T]{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.HasMore = true
+		page.NextCursor = cursorOf(page.Items[limit-1]).Encode()
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+
+	return page
+}
+
+// Slice paginates an in-memory list the same way FindPage paginates a
+// collection. The items are sorted in place.
+func Slice[T any](items []T, p Params, desc bool, cursorOf func(T) Cursor) *Page[T] {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := cursorOf(items[i]), cursorOf(items[j])
+		if desc {
+			return Less(b, a)
+		}
+		return Less(a, b)
+	})
+
+	var page []T
+	for _, item := range items {
+		if !p.Cursor.Follows(cursorOf(item), desc) {
+			continue
+		}
+		page = append(page, item)
+		if len(page) > p.Limit {
+			break
+		}
+	}
+
+	return NewPage(page, p.Limit, cursorOf)
+}
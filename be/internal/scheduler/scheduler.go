@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is a unit of background work. A failed run is logged and the job
+// simply runs again on the next tick.
+type Job func(ctx context.Context) error
+
+// Scheduler runs jobs at fixed intervals until its context is cancelled
+type Scheduler struct {
+	jobs []scheduledJob
+	wg   sync.WaitGroup
+}
+
+type scheduledJob struct {
+	name     string
+	interval time.Duration
+	run      Job
+}
+
+// New creates a new scheduler
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every registers a job to run once at start and then every interval
+func (s *Scheduler) Every(name string, interval time.Duration, job Job) {
+	s.jobs = append(s.jobs, scheduledJob{name: name, interval: interval, run: job})
+}
+
+// Start runs every registered job in its own goroutine until ctx is done
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.loop(ctx, job)
+		}()
+	}
+}
+
+// Wait blocks until every job has returned after ctx was cancelled
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// loop runs a job immediately and then on every tick. Runs never overlap:
+// a tick that fires while the job is still running is skipped.
+func (s *Scheduler) loop(ctx context.Context, job scheduledJob) {
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job.run(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Scheduled job %s failed: %v", job.name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
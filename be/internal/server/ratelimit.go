@@ -0,0 +1,273 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"prayerreq-backend/internal/apierror"
+	"prayerreq-backend/internal/auth"
+	"prayerreq-backend/internal/config"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Headers sent with rate limited responses, following the IETF RateLimit
+// header fields draft
+const (
+	rateLimitLimitHeader     = "RateLimit-Limit"
+	rateLimitRemainingHeader = "RateLimit-Remaining"
+	rateLimitResetHeader     = "RateLimit-Reset"
+	rateLimitPolicyHeader    = "RateLimit-Policy"
+	retryAfterHeader         = "Retry-After"
+)
+
+// rateLimitHeaders are exposed to browsers through CORS
+var rateLimitHeaders = []string{
+	rateLimitLimitHeader,
+	rateLimitRemainingHeader,
+	rateLimitResetHeader,
+	rateLimitPolicyHeader,
+	retryAfterHeader,
+}
+
+// RateLimitStore keeps the token buckets. Implementations must be safe for
+// concurrent use; a store shared between instances, such as Redis, makes
+// them enforce a single limit.
+type RateLimitStore interface {
+	// Take removes a token from the bucket for key. A bucket holds up to
+	// rate.Limit tokens and refills evenly over rate.Period.
+	Take(ctx context.Context, key string, rate config.Rate, now time.Time) (RateLimitResult, error)
+}
+
+// RateLimitResult is the state of a bucket after a Take
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int           // whole tokens left
+	RetryAfter time.Duration // until the next token, when not allowed
+	ResetAfter time.Duration // until the bucket is full again
+}
+
+// rateLimitPolicy throttles the requests to a route
+type rateLimitPolicy struct {
+	name string
+	rate config.Rate
+	byIP bool // key on the client IP even for logged-in users
+}
+
+// RateLimiter throttles write endpoints with a token bucket per route and
+// client. Clients are the authenticated user, or the IP address for
+// anonymous requests and routes such as login where the user is not known.
+type RateLimiter struct {
+	store          RateLimitStore
+	enabled        bool
+	trustedProxies []netip.Prefix
+	policies       map[string]rateLimitPolicy // by method and route pattern
+}
+
+// NewRateLimiter creates a rate limiter with the policies from the configuration
+func NewRateLimiter(store RateLimitStore, cfg config.RateLimit) *RateLimiter {
+	authPolicy := rateLimitPolicy{name: "auth", rate: cfg.Auth, byIP: true}
+
+	return &RateLimiter{
+		store:          store,
+		enabled:        cfg.Enabled,
+		trustedProxies: cfg.TrustedProxies,
+		policies: map[string]rateLimitPolicy{
+			"POST /api/v1/prayers":                {name: "create_prayer", rate: cfg.CreatePrayer},
+			"POST /api/v1/prayers/{id}/pray":      {name: "pray", rate: cfg.Pray},
+			"POST /api/v1/prayers/{id}/reactions": {name: "pray", rate: cfg.Pray},
+			"POST /api/v1/prayers/{id}/comments":  {name: "comment", rate: cfg.Comment},
+			"POST /api/v1/trips/{id}/requests":    {name: "trip_request", rate: cfg.TripRequest},
+			"POST /api/v1/auth/login":             authPolicy,
+			"POST /api/v1/auth/register":          authPolicy,
+			"POST /api/v1/auth/refresh":           authPolicy,
+		},
+	}
+}
+
+// Middleware enforces the policy of the route a request is for. It must run
+// after authentication so logged-in users get their own bucket. routes is
+// the root router, used to find the route pattern before routing happens.
+func (l *RateLimiter) Middleware(routes chi.Routes) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !l.enabled {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rctx := chi.NewRouteContext()
+			if !routes.Match(rctx, r.Method, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			policy, ok := l.policies[r.Method+" "+rctx.RoutePattern()]
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			result, err := l.store.Take(r.Context(), policy.name+":"+l.clientKey(r, policy), policy.rate, time.Now())
+			if err != nil {
+				// Fail open: an unavailable store shouldn't take the API down
+				log.Printf("Rate limit store failed, allowing request: %v", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header := w.Header()
+			header.Set(rateLimitLimitHeader, strconv.Itoa(policy.rate.Limit))
+			header.Set(rateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+			header.Set(rateLimitResetHeader, strconv.Itoa(ceilSeconds(result.ResetAfter)))
+			header.Set(rateLimitPolicyHeader, fmt.Sprintf("%d;w=%d", policy.rate.Limit, ceilSeconds(policy.rate.Period)))
+
+			if !result.Allowed {
+				retryAfter := ceilSeconds(result.RetryAfter)
+				header.Set(retryAfterHeader, strconv.Itoa(retryAfter))
+				apierror.Write(w, r, apierror.New(http.StatusTooManyRequests, apierror.CodeRateLimited,
+					fmt.Sprintf("Too many requests; try again in %d seconds", retryAfter)))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientKey identifies the client a request counts against
+func (l *RateLimiter) clientKey(r *http.Request, policy rateLimitPolicy) string {
+	if user := auth.UserFromContext(r.Context()); user != nil && !policy.byIP {
+		return "user:" + user.ID.Hex()
+	}
+
+	ip := l.clientIP(r)
+	if ip.Is6() {
+		// A single IPv6 client usually controls a whole /64
+		return "ip:" + netip.PrefixFrom(ip, 64).Masked().String()
+	}
+	return "ip:" + ip.String()
+}
+
+// clientIP returns the address of the client. Requests from a trusted proxy
+// are attributed to the address it forwarded for: X-Forwarded-For is read
+// from the nearest hop back, and the first address that isn't a trusted
+// proxy is the client. Without trusted proxies the header is ignored, since
+// any client can set it.
+func (l *RateLimiter) clientIP(r *http.Request) netip.Addr {
+	addrPort, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return netip.IPv4Unspecified()
+	}
+	ip := addrPort.Addr().Unmap()
+	if !l.isTrustedProxy(ip) {
+		return ip
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		ip = hop.Unmap()
+		if !l.isTrustedProxy(ip) {
+			break
+		}
+	}
+	return ip
+}
+
+// isTrustedProxy reports whether ip belongs to a trusted proxy
+func (l *RateLimiter) isTrustedProxy(ip netip.Addr) bool {
+	for _, prefix := range l.trustedProxies {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ceilSeconds rounds a duration up to whole seconds
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// memoryBucket is a token bucket held by MemoryRateLimitStore
+type memoryBucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time // when the bucket will have refilled completely
+}
+
+// memorySweepInterval is how often full buckets are dropped from memory
+const memorySweepInterval = time.Minute
+
+// MemoryRateLimitStore keeps token buckets in process memory. Each instance
+// of the API enforces its limits separately.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+}
+
+// NewMemoryRateLimitStore creates an empty in-memory store
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: make(map[string]*memoryBucket),
+	}
+}
+
+// Take removes a token from the bucket for key
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, rate config.Rate, now time.Time) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	limit := float64(rate.Limit)
+	perToken := rate.Period / time.Duration(rate.Limit)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{tokens: limit, updated: now}
+		s.buckets[key] = b
+	} else if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = min(limit, b.tokens+float64(elapsed)/float64(perToken))
+		b.updated = now
+	}
+
+	var result RateLimitResult
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) * float64(perToken))
+	}
+	result.Remaining = int(b.tokens)
+	result.ResetAfter = time.Duration((limit - b.tokens) * float64(perToken))
+	b.full = now.Add(result.ResetAfter)
+
+	return result, nil
+}
+
+// sweep drops the buckets that have refilled, since a new bucket starts
+// full anyway. Callers must hold the lock.
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -1,9 +1,19 @@
 package server
 
 import (
+	"context"
+	"errors"
+	"log"
 	"net/http"
+	"time"
 
+	"prayerreq-backend/internal/apierror"
+	"prayerreq-backend/internal/auth"
+	"prayerreq-backend/internal/config"
+	"prayerreq-backend/internal/controller/moderation"
+	"prayerreq-backend/internal/controller/notification"
 	"prayerreq-backend/internal/controller/prayer"
+	"prayerreq-backend/internal/controller/trip"
 	"prayerreq-backend/internal/controller/user"
 
 	"github.com/go-chi/chi/v5"
@@ -13,48 +23,123 @@ import (
 
 // Server represents the HTTP server
 type Server struct {
-	router *chi.Mux
+	router          *chi.Mux
+	httpServer      *http.Server
+	shutdownTimeout time.Duration
 }
 
 // New creates a new server instance
-func New(prayerHandler *prayer.HTTPHandler, userHandler *user.HTTPHandler) *Server {
+func New(cfg *config.Config, authenticator *auth.Authenticator, limiter *RateLimiter, prayerHandler *prayer.HTTPHandler, userHandler *user.HTTPHandler, tripHandler *trip.HTTPHandler, notificationHandler *notification.HTTPHandler, moderationHandler *moderation.HTTPHandler) *Server {
 	r := chi.NewRouter()
 
 	// Middleware
-	r.Use(middleware.Logger)
+	r.Use(middleware.RequestID)
+	r.Use(exposeRequestID)
+	if cfg.LogLevel == "debug" || cfg.LogLevel == "info" {
+		r.Use(middleware.Logger)
+	}
 	r.Use(middleware.Recoverer)
 	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"*"},
+		AllowedOrigins:   cfg.CORS.AllowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
-		AllowCredentials: false, // Must be false when using wildcard origins
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", auth.DeviceIDHeader},
+		ExposedHeaders:   append([]string{"Link", requestIDHeader}, rateLimitHeaders...),
+		AllowCredentials: false, // Tokens travel in the Authorization header, not cookies
 		MaxAge:           300,
 	}))
 
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		apierror.Write(w, r, apierror.NotFound(apierror.CodeNotFound, "No route matches "+r.URL.Path))
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		apierror.Write(w, r, apierror.New(http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed,
+			r.Method+" is not allowed on "+r.URL.Path))
+	})
+
 	// Health check
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
 	})
 
-	// API routes
+	// API routes; the rate limiter looks up route patterns on the root router
+	root := r
 	r.Route("/api/v1", func(r chi.Router) {
+		r.Use(authenticator.Middleware)
+		r.Use(limiter.Middleware(root))
+
 		prayerHandler.RegisterRoutes(r)
 		userHandler.RegisterRoutes(r)
+		tripHandler.RegisterRoutes(r)
+		notificationHandler.RegisterRoutes(r)
+		moderationHandler.RegisterRoutes(r)
 	})
 
 	return &Server{
 		router: r,
+		httpServer: &http.Server{
+			Addr:              ":" + cfg.Server.Port,
+			Handler:           r,
+			ReadTimeout:       cfg.Server.ReadTimeout,
+			ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+			WriteTimeout:      cfg.Server.WriteTimeout,
+			IdleTimeout:       cfg.Server.IdleTimeout,
+		},
+		shutdownTimeout: cfg.Server.ShutdownTimeout,
 	}
 }
 
+// requestIDHeader echoes the request ID so clients can quote it in bug reports
+const requestIDHeader = "X-Request-Id"
+
+// exposeRequestID sets the request ID assigned by middleware.RequestID on the response
+func exposeRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(requestIDHeader, middleware.GetReqID(r.Context()))
+		next.ServeHTTP(w, r)
+	})
+}
+
 // ServeHTTP implements the http.Handler interface
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.router.ServeHTTP(w, r)
 }
 
-// Start starts the server on the given port
-func (s *Server) Start(port string) error {
-	return http.ListenAndServe(":"+port, s.router)
+// RegisterOnShutdown registers a function to call when shutdown begins,
+// such as closing long-lived streams so they don't hold the drain open
+func (s *Server) RegisterOnShutdown(f func()) {
+	s.httpServer.RegisterOnShutdown(f)
+}
+
+// Run serves requests until ctx is cancelled, then stops accepting new
+// connections and waits up to the shutdown timeout for in-flight requests
+func (s *Server) Run(ctx context.Context) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Printf("Server starting on %s", s.httpServer.Addr)
+		serveErr <- s.httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Println("Shutting down server, draining in-flight requests")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+		// Drop whatever is still open rather than hang the process
+		s.httpServer.Close()
+		return err
+	}
+
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	log.Println("Server stopped")
+	return nil
 }
@@ -0,0 +1,236 @@
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"prayerreq-backend/internal/apierror"
+)
+
+// FieldError describes a single failed rule
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Struct checks the `validate` tags of a struct (or pointer to struct) and
+// returns a validation_failed API error listing every failed field, or nil.
+//
+// Supported rules, applied left to right:
+//
+//	required   the value must not be empty (blank strings count as empty)
+//	omitempty  skip the remaining rules when the value is empty or nil
+//	email      the value must be a bare email address
+//	min=N      strings need at least N characters, slices N items, numbers a value of N
+//	max=N      strings allow at most N characters, slices N items, numbers a value of N
+//	maxbytes=N strings allow at most N bytes of UTF-8
+//	oneof=a b  the value must be one of the space separated options
+//	dive       apply the remaining rules to every element of a slice
+//
+// Pointers are dereferenced, so `omitempty,required` on a *string accepts nil
+// but rejects a pointer to an empty or blank string, and `omitempty` only
+// skips the other rules for a nil pointer. Nested structs are validated
+// recursively and their fields reported with a dotted path.
+func Struct(v any) error {
+	var errs []FieldError
+	validateStruct(reflect.ValueOf(v), "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return apierror.BadRequest(apierror.CodeValidationFailed, errs[0].Message).WithDetails(errs)
+}
+
+// validateStruct checks every tagged field of a struct value
+func validateStruct(v reflect.Value, prefix string, errs *[]FieldError) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := prefix + fieldName(field)
+		if tag := field.Tag.Get("validate"); tag != "" && tag != "-" {
+			validateValue(v.Field(i), name, strings.Split(tag, ","), errs)
+		} else {
+			validateStruct(v.Field(i), name+".", errs)
+		}
+	}
+}
+
+// validateValue applies the rules to a single value
+func validateValue(v reflect.Value, name string, rules []string, errs *[]FieldError) {
+	for i, rule := range rules {
+		key, param, _ := strings.Cut(rule, "=")
+
+		switch key {
+		case "omitempty":
+			// A nil pointer is a field that wasn't sent; a pointer to an
+			// empty value was sent empty and goes on to the other rules
+			if (v.Kind() == reflect.Pointer && v.IsNil()) || (v.Kind() != reflect.Pointer && isEmpty(v)) {
+				return
+			}
+			continue
+		case "required":
+			if isEmpty(v) {
+				addError(errs, name, key, "%s is required", name)
+				return
+			}
+			continue
+		}
+
+		// The remaining rules look at the value behind any pointer
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return
+			}
+			v = v.Elem()
+		}
+
+		switch key {
+		case "dive":
+			if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+				panic("validate: dive on non-slice field " + name)
+			}
+			for j := 0; j < v.Len(); j++ {
+				validateValue(v.Index(j), fmt.Sprintf("%s[%d]", name, j), rules[i+1:], errs)
+			}
+			return
+		case "email":
+			if addr, err := mail.ParseAddress(v.String()); err != nil || addr.Address != strings.TrimSpace(v.String()) {
+				addError(errs, name, key, "%s must be a valid email address", name)
+				return
+			}
+		case "min", "max":
+			if !checkBound(v, key, param) {
+				addError(errs, name, key, "%s must %s", name, describeBound(v, key, param))
+				return
+			}
+		case "maxbytes":
+			n, err := strconv.Atoi(param)
+			if err != nil {
+				panic("validate: invalid bound " + param)
+			}
+			if len(v.String()) > n {
+				addError(errs, name, key, "%s must be at most %s bytes long", name, param)
+				return
+			}
+		case "oneof":
+			options := strings.Fields(param)
+			if !slices.Contains(options, fmt.Sprint(v.Interface())) {
+				addError(errs, name, key, "%s must be one of: %s", name, strings.Join(options, ", "))
+				return
+			}
+		default:
+			panic("validate: unknown rule " + key + " on field " + name)
+		}
+	}
+
+	// Nested structs carry their own tags
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		validateStruct(v, name+".", errs)
+	}
+}
+
+// checkBound evaluates a min or max rule
+func checkBound(v reflect.Value, key, param string) bool {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		panic("validate: invalid bound " + param)
+	}
+
+	var actual float64
+	switch v.Kind() {
+	case reflect.String:
+		actual = float64(utf8.RuneCountInString(v.String()))
+	case reflect.Slice, reflect.Array, reflect.Map:
+		actual = float64(v.Len())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = float64(v.Int())
+	case reflect.Float32, reflect.Float64:
+		actual = v.Float()
+	default:
+		panic("validate: " + key + " on unsupported kind " + v.Kind().String())
+	}
+
+	if key == "min" {
+		return actual >= n
+	}
+	return actual <= n
+}
+
+// describeBound phrases a min or max rule for an error message
+func describeBound(v reflect.Value, key, param string) string {
+	limit := "at least"
+	if key == "max" {
+		limit = "at most"
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("be %s %s characters", limit, param)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fmt.Sprintf("contain %s %s items", limit, param)
+	default:
+		return fmt.Sprintf("be %s %s", limit, param)
+	}
+}
+
+// isEmpty reports whether a value counts as missing, looking behind pointers
+func isEmpty(v reflect.Value) bool {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return true
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
+// fieldName returns the JSON name of a struct field
+func fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+func addError(errs *[]FieldError, field, rule, format string, args ...any) {
+	*errs = append(*errs, FieldError{
+		Field:   field,
+		Rule:    rule,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
@@ -0,0 +1,108 @@
+package validate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"prayerreq-backend/internal/apierror"
+)
+
+type updateInput struct {
+	Title    *string `json:"title" validate:"omitempty,required,max=5"`
+	Avatar   *string `json:"avatar" validate:"omitempty,max=5"`
+	Priority *string `json:"priority" validate:"omitempty,oneof=low high"`
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+// failedRules returns the rule that failed for each field
+func failedRules(t *testing.T, err error) map[string]string {
+	t.Helper()
+	rules := map[string]string{}
+	if err == nil {
+		return rules
+	}
+
+	var apiErr *apierror.Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Struct returned %T, want *apierror.Error", err)
+	}
+	for _, fe := range apiErr.Details.([]FieldError) {
+		rules[fe.Field] = fe.Rule
+	}
+	return rules
+}
+
+func TestStructPointerFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		input updateInput
+		want  map[string]string
+	}{
+		{"nil pointers are skipped", updateInput{}, map[string]string{}},
+		{"valid values", updateInput{Title: ptr("Shifa"), Avatar: ptr("a.png"), Priority: ptr("low")}, map[string]string{}},
+		{"empty required string", updateInput{Title: ptr("")}, map[string]string{"title": "required"}},
+		{"blank required string", updateInput{Title: ptr("   ")}, map[string]string{"title": "required"}},
+		{"too long", updateInput{Title: ptr("Sabr and shifa")}, map[string]string{"title": "max"}},
+		{"empty optional string", updateInput{Avatar: ptr("")}, map[string]string{}},
+		{"empty string checked against oneof", updateInput{Priority: ptr("")}, map[string]string{"priority": "oneof"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := failedRules(t, Struct(tt.input))
+			if len(got) != len(tt.want) {
+				t.Fatalf("failed rules = %v, want %v", got, tt.want)
+			}
+			for field, rule := range tt.want {
+				if got[field] != rule {
+					t.Errorf("field %s failed %q, want %q", field, got[field], rule)
+				}
+			}
+		})
+	}
+}
+
+func TestStructRequired(t *testing.T) {
+	type input struct {
+		Name string   `json:"name" validate:"required"`
+		Tags []string `json:"tags" validate:"required"`
+	}
+
+	got := failedRules(t, Struct(input{Name: " \t"}))
+	if got["name"] != "required" || got["tags"] != "required" {
+		t.Errorf("failed rules = %v, want name and tags required", got)
+	}
+
+	if err := Struct(input{Name: "Amina", Tags: []string{"health"}}); err != nil {
+		t.Errorf("Struct(valid) = %v, want nil", err)
+	}
+}
+
+func TestStructMaxBytes(t *testing.T) {
+	type input struct {
+		Password string `json:"password" validate:"required,min=8,maxbytes=72"`
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		want     string
+	}{
+		{"72 ASCII bytes", strings.Repeat("a", 72), ""},
+		{"73 ASCII bytes", strings.Repeat("a", 73), "maxbytes"},
+		{"36 two-byte characters", strings.Repeat("س", 36), ""},
+		{"40 two-byte characters", strings.Repeat("س", 40), "maxbytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := failedRules(t, Struct(input{Password: tt.password}))["password"]; got != tt.want {
+				t.Errorf("password failed %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import { Input } from "@/components/ui/input";
 import { Label } from "@/components/ui/label";
 import { Alert, AlertDescription } from "@/components/ui/alert";
 import { Loader2, Mail, Lock, User, AlertCircle } from "lucide-react";
+import { api } from "@/services/api";
 
 interface User {
   id: string;
@@ -53,22 +54,22 @@ export default function AuthModals({
     setLoading(true);
     setError("");
 
-    // Simulate API call
-    setTimeout(() => {
-      if (signInData.email && signInData.password) {
-        const user: User = {
-          id: "user_" + Date.now(),
-          name: signInData.email.split("@")[0],
-          email: signInData.email,
-        };
-        onSignIn(user);
-        setSignInData({ email: "", password: "" });
-        onSignInClose();
-      } else {
-        setError("Please fill in all fields");
-      }
+    if (!signInData.email || !signInData.password) {
+      setError("Please fill in all fields");
       setLoading(false);
-    }, 1000);
+      return;
+    }
+
+    try {
+      const { user } = await api.login(signInData.email, signInData.password);
+      onSignIn({ id: user.id, name: user.name, email: user.email });
+      setSignInData({ email: "", password: "" });
+      onSignInClose();
+    } catch {
+      setError("Invalid email or password");
+    } finally {
+      setLoading(false);
+    }
   };
 
   const handleSignUp = async (e: React.FormEvent) => {
@@ -82,27 +83,31 @@ export default function AuthModals({
       return;
     }
 
-    // Simulate API call
-    setTimeout(() => {
-      if (signUpData.name && signUpData.email && signUpData.password) {
-        const user: User = {
-          id: "user_" + Date.now(),
-          name: signUpData.name,
-          email: signUpData.email,
-        };
-        onSignIn(user);
-        setSignUpData({
-          name: "",
-          email: "",
-          password: "",
-          confirmPassword: "",
-        });
-        onSignUpClose();
-      } else {
-        setError("Please fill in all fields");
-      }
+    if (!signUpData.name || !signUpData.email || !signUpData.password) {
+      setError("Please fill in all fields");
       setLoading(false);
-    }, 1000);
+      return;
+    }
+
+    try {
+      const { user } = await api.register({
+        name: signUpData.name,
+        email: signUpData.email,
+        password: signUpData.password,
+      });
+      onSignIn({ id: user.id, name: user.name, email: user.email });
+      setSignUpData({
+        name: "",
+        email: "",
+        password: "",
+        confirmPassword: "",
+      });
+      onSignUpClose();
+    } catch {
+      setError("Could not create your account. Please try again.");
+    } finally {
+      setLoading(false);
+    }
   };
 
   return (
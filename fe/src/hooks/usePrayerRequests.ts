@@ -40,8 +40,8 @@ export function usePrayerRequests() {
     try {
       setLoading(true);
       setError(null);
-      const backendData = await api.getPrayerRequests();
-      const transformedData = backendData.map(transformPrayerRequest);
+      const page = await api.getPrayerRequests({ limit: 100 });
+      const transformedData = page.items.map(transformPrayerRequest);
       setPrayerRequests(transformedData);
     } catch (err) {
       console.error("Failed to load prayer requests:", err);
@@ -74,14 +74,14 @@ export function usePrayerRequests() {
   const incrementPrayCount = async (id: string): Promise<boolean> => {
     try {
       setError(null);
-      await api.incrementPrayCount(id);
+      const result = await api.incrementPrayCount(id);
 
       setPrayerRequests((prev) =>
         prev.map((request) =>
           request.id === id
             ? {
                 ...request,
-                prayedFor: request.prayedFor + 1,
+                prayedFor: result.pray_count,
                 prayedByUser: true,
               }
             : request
@@ -116,8 +116,8 @@ export function usePrayerRequests() {
   ): Promise<PrayerRequest[]> => {
     try {
       setError(null);
-      const backendData = await api.searchPrayerRequests(query);
-      return backendData.map(transformPrayerRequest);
+      const page = await api.searchPrayerRequests(query);
+      return page.items.map(transformPrayerRequest);
     } catch (err) {
       console.error("Failed to search prayer requests:", err);
       setError("Failed to search prayer requests. Please try again.");
@@ -131,8 +131,8 @@ export function usePrayerRequests() {
   ): Promise<PrayerRequest[]> => {
     try {
       setError(null);
-      const backendData = await api.getPrayersByCategory(category);
-      return backendData.map(transformPrayerRequest);
+      const page = await api.getPrayersByCategory(category);
+      return page.items.map(transformPrayerRequest);
     } catch (err) {
       console.error("Failed to get prayers by category:", err);
       setError("Failed to get prayers by category. Please try again.");
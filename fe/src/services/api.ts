@@ -6,16 +6,63 @@ export interface PrayerRequest {
   id: string;
   title: string;
   description: string;
-  user_id: string;
+  user_id?: string; // only sent to the owner and staff
   user_name: string;
   is_anonymous: boolean;
   is_answered: boolean;
+  answered_at?: string;
+  testimony?: string;
   priority: string;
   category: string;
   tags: string[];
+  location?: PrayerLocation;
   pray_count: number;
+  reaction_counts: Partial<Record<ReactionType, number>>;
+  saved_count: number;
+  comment_count: number;
   created_at: string;
   updated_at: string;
+  expires_at?: string;
+  archived_at?: string;
+  deleted_at?: string;
+  moderation_status: ModerationStatus;
+  moderation_reasons?: string[];
+  report_count?: number;
+}
+
+// Flagged content is only visible to its author and administrators
+export type ModerationStatus = "published" | "pending_review" | "rejected";
+
+export type ReportReason = "spam" | "abuse" | "inappropriate" | "other";
+
+export interface ReportResult {
+  reported: boolean;
+  counted: boolean;
+}
+
+export type HolySite =
+  | "masjid_al_haram"
+  | "masjid_an_nabawi"
+  | "masjid_al_aqsa"
+  | "arafat"
+  | "mina"
+  | "muzdalifah"
+  | "masjid_quba";
+
+export const HOLY_SITE_NAMES: Record<HolySite, string> = {
+  masjid_al_haram: "Masjid al-Haram",
+  masjid_an_nabawi: "Masjid an-Nabawi",
+  masjid_al_aqsa: "Masjid al-Aqsa",
+  arafat: "Arafat",
+  mina: "Mina",
+  muzdalifah: "Muzdalifah",
+  masjid_quba: "Masjid Quba",
+};
+
+export interface PrayerLocation {
+  place: string;
+  holy_site?: HolySite;
+  coordinates?: { latitude: number; longitude: number };
 }
 
 export interface CreatePrayerRequestInput {
@@ -26,15 +73,261 @@ export interface CreatePrayerRequestInput {
   priority?: string;
   category?: string;
   tags?: string[];
+  location?: PrayerLocation;
+  expires_at?: string;
+}
+
+// Deleted comments are tombstones with deleted_at set and no message
+export interface PrayerComment {
+  id: string;
+  prayer_request_id: string;
+  parent_id?: string;
+  user_name: string;
+  message: string;
+  is_anonymous: boolean;
+  created_at: string;
+  edited_at?: string;
+  deleted_at?: string;
+  replies?: PrayerComment[];
+  moderation_status: ModerationStatus;
+  moderation_reasons?: string[];
+  report_count?: number;
+}
+
+export interface CreateCommentInput {
+  message: string;
+  parent_id?: string;
+  user_name?: string;
+  is_anonymous?: boolean;
+}
+
+export interface PrayResult {
+  message: string;
+  prayed: boolean;
+  counted: boolean;
+  pray_count: number;
+}
+
+// Praying is the "pray" reaction; pray_count mirrors its count
+export type ReactionType = "pray" | "ameen" | "prayed_at_haram" | "made_dua";
+
+export interface ReactionResult {
+  type: ReactionType;
+  reacted: boolean;
+  counted: boolean;
+  reaction_counts: Partial<Record<ReactionType, number>>;
+}
+
+export interface ReactionStatus {
+  reactions: ReactionType[];
+  reaction_counts: Partial<Record<ReactionType, number>>;
+}
+
+export interface SaveResult {
+  saved: boolean;
+  saved_count: number;
+}
+
+export interface SavedPrayerRequest extends PrayerRequest {
+  saved_at: string;
+}
+
+export interface PrayerNotification {
+  id: string;
+  type: "prayer_answered";
+  prayer_request_id: string;
+  message: string;
+  read_at?: string;
+  created_at: string;
 }
 
 export interface PrayerStats {
   total_prayers: number;
   total_pray_count: number;
+  total_comments: number;
   answered_prayers: number;
   urgent_prayers: number;
   categories_count: Record<string, number>;
-  recent_activity: any[];
+  locations_count: Record<string, number>;
+  recent_activity: ActivityItem[];
+}
+
+export interface ActivityItem {
+  id: string;
+  type:
+    | "prayer_created"
+    | "prayer_answered"
+    | "pray_count_increased"
+    | "comment_added";
+  prayer_request_id: string;
+  message: string;
+  created_at: string;
+}
+
+export interface User {
+  id: string;
+  email: string;
+  name: string;
+  avatar: string;
+  is_active: boolean;
+  role: UserRole;
+  created_at: string;
+  updated_at: string;
+}
+
+export type UserRole = "member" | "moderator" | "admin";
+
+export interface AuthResponse {
+  user: User;
+  access_token: string;
+  refresh_token: string;
+  token_type: string;
+  expires_in: number;
+}
+
+const ACCESS_TOKEN_KEY = "prayerreq.access_token";
+const REFRESH_TOKEN_KEY = "prayerreq.refresh_token";
+const DEVICE_ID_KEY = "prayerreq.device_id";
+
+// Anonymous device token, used to count each visitor's prayer only once
+function deviceId(): string {
+  let id = localStorage.getItem(DEVICE_ID_KEY);
+  if (!id) {
+    id = crypto.randomUUID();
+    localStorage.setItem(DEVICE_ID_KEY, id);
+  }
+  return id;
+}
+
+// Error returned by the API as {"error": {"code", "message", ...}}
+export class ApiError extends Error {
+  status: number;
+  code: string;
+  details?: unknown;
+  requestId?: string;
+
+  constructor(
+    status: number,
+    code: string,
+    message: string,
+    details?: unknown,
+    requestId?: string
+  ) {
+    super(message);
+    this.name = "ApiError";
+    this.status = status;
+    this.code = code;
+    this.details = details;
+    this.requestId = requestId;
+  }
+}
+
+// Paginated list response
+export interface Page<T> {
+  items: T[];
+  next_cursor: string;
+  has_more: boolean;
+}
+
+export interface PageParams {
+  limit?: number;
+  cursor?: string;
+}
+
+export interface PrayerFilters {
+  category?: string;
+  priority?: string;
+  tag?: string[];
+  is_answered?: boolean;
+  user_id?: string;
+  created_after?: string;
+  created_before?: string;
+  min_pray_count?: number;
+  holy_site?: HolySite;
+  place?: string;
+  include_archived?: boolean;
+  sort?:
+    | "-created_at"
+    | "created_at"
+    | "-pray_count"
+    | "pray_count"
+    | "-updated_at"
+    | "updated_at";
+}
+
+function filterQuery(filters: PrayerFilters | TripFilters = {}): string {
+  const query = new URLSearchParams();
+  for (const [key, value] of Object.entries(filters)) {
+    if (value === undefined || value === "") continue;
+    if (Array.isArray(value)) {
+      value.forEach((v) => query.append(key, v));
+    } else {
+      query.set(key, String(value));
+    }
+  }
+  return query.toString();
+}
+
+export interface SearchFilters {
+  category?: string;
+  priority?: PrayerRequest["priority"];
+  is_answered?: boolean;
+  include_archived?: boolean;
+}
+
+export interface SearchResult extends PrayerRequest {
+  score: number;
+}
+
+export interface Trip {
+  id: string;
+  user_id: string;
+  user_name: string;
+  destination: "makkah" | "madinah" | "al_aqsa";
+  type: "hajj" | "umrah";
+  description: string;
+  start_date: string;
+  end_date: string;
+  request_count: number;
+  prayed_count: number;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface CreateTripInput {
+  destination: Trip["destination"];
+  type: Trip["type"];
+  description?: string;
+  start_date: string;
+  end_date: string;
+}
+
+export interface TripFilters {
+  destination?: Trip["destination"];
+  type?: Trip["type"];
+  user_id?: string;
+  include_past?: boolean;
+}
+
+export interface TripRequest {
+  id: string;
+  trip_id: string;
+  user_id?: string; // only sent to the requester and staff
+  user_name: string;
+  is_anonymous: boolean;
+  message: string;
+  status: "pending" | "prayed";
+  prayed_at?: string;
+  prayed_place?: string;
+  created_at: string;
+}
+
+function pageQuery(params: PageParams = {}): string {
+  const query = new URLSearchParams();
+  if (params.limit) query.set("limit", String(params.limit));
+  if (params.cursor) query.set("cursor", params.cursor);
+  const qs = query.toString();
+  return qs ? `?${qs}` : "";
 }
 
 // API Service class
@@ -52,9 +345,12 @@ class ApiService {
   ): Promise<T> {
     const url = `${this.baseUrl}${endpoint}`;
 
+    const accessToken = localStorage.getItem(ACCESS_TOKEN_KEY);
     const defaultOptions: RequestInit = {
       headers: {
         "Content-Type": "application/json",
+        "X-Device-ID": deviceId(),
+        ...(accessToken ? { Authorization: `Bearer ${accessToken}` } : {}),
         ...options.headers,
       },
     };
@@ -65,7 +361,19 @@ class ApiService {
       const response = await fetch(url, config);
 
       if (!response.ok) {
-        throw new Error(`HTTP error! status: ${response.status}`);
+        const body = await response.json().catch(() => null);
+        const error = body?.error;
+        throw new ApiError(
+          response.status,
+          error?.code ?? "http_error",
+          error?.message ?? `HTTP error! status: ${response.status}`,
+          error?.details,
+          error?.request_id
+        );
+      }
+
+      if (response.status === 204) {
+        return undefined as T;
       }
 
       return await response.json();
@@ -75,9 +383,66 @@ class ApiService {
     }
   }
 
+  // Auth API methods
+  async register(data: {
+    name: string;
+    email: string;
+    password: string;
+  }): Promise<AuthResponse> {
+    const auth = await this.request<AuthResponse>("/auth/register", {
+      method: "POST",
+      body: JSON.stringify(data),
+    });
+    this.storeTokens(auth);
+    return auth;
+  }
+
+  async login(email: string, password: string): Promise<AuthResponse> {
+    const auth = await this.request<AuthResponse>("/auth/login", {
+      method: "POST",
+      body: JSON.stringify({ email, password }),
+    });
+    this.storeTokens(auth);
+    return auth;
+  }
+
+  async refresh(): Promise<AuthResponse> {
+    const auth = await this.request<AuthResponse>("/auth/refresh", {
+      method: "POST",
+      body: JSON.stringify({
+        refresh_token: localStorage.getItem(REFRESH_TOKEN_KEY),
+      }),
+    });
+    this.storeTokens(auth);
+    return auth;
+  }
+
+  async me(): Promise<User> {
+    return this.request<User>("/auth/me");
+  }
+
+  logout(): void {
+    localStorage.removeItem(ACCESS_TOKEN_KEY);
+    localStorage.removeItem(REFRESH_TOKEN_KEY);
+  }
+
+  private storeTokens(auth: AuthResponse): void {
+    localStorage.setItem(ACCESS_TOKEN_KEY, auth.access_token);
+    localStorage.setItem(REFRESH_TOKEN_KEY, auth.refresh_token);
+  }
+
   // Prayer Request API methods
-  async getPrayerRequests(): Promise<PrayerRequest[]> {
-    return this.request<PrayerRequest[]>("/prayers");
+  // Cursors are tied to the sort order; pass the same filters and sort
+  // when requesting the next page
+  async getPrayerRequests(
+    params?: PageParams,
+    filters?: PrayerFilters
+  ): Promise<Page<PrayerRequest>> {
+    const filter = filterQuery(filters);
+    const qs = pageQuery(params);
+    return this.request<Page<PrayerRequest>>(
+      `/prayers${qs}${filter ? (qs ? "&" : "?") + filter : ""}`
+    );
   }
 
   async getPrayerRequest(id: string): Promise<PrayerRequest> {
@@ -109,20 +474,147 @@ class ApiService {
     });
   }
 
-  async incrementPrayCount(id: string): Promise<{ message: string }> {
-    return this.request<{ message: string }>(`/prayers/${id}/pray`, {
+  async restorePrayerRequest(id: string): Promise<PrayerRequest> {
+    return this.request<PrayerRequest>(`/prayers/${id}/restore`, {
       method: "POST",
     });
   }
 
-  async searchPrayerRequests(query: string): Promise<PrayerRequest[]> {
-    return this.request<PrayerRequest[]>(
-      `/prayers/search?q=${encodeURIComponent(query)}`
+  // Expired requests of a user; defaults to the logged-in user. Requires login
+  async getArchived(
+    userId?: string,
+    params?: PageParams
+  ): Promise<Page<PrayerRequest>> {
+    const qs = pageQuery(params);
+    const user = userId ? `user_id=${encodeURIComponent(userId)}` : "";
+    return this.request<Page<PrayerRequest>>(
+      `/prayers/archived${qs}${user ? (qs ? "&" : "?") + user : ""}`
     );
   }
 
-  async getPrayersByCategory(category: string): Promise<PrayerRequest[]> {
-    return this.request<PrayerRequest[]>(`/prayers/category/${category}`);
+  async getTrash(params?: PageParams): Promise<Page<PrayerRequest>> {
+    return this.request<Page<PrayerRequest>>(
+      `/prayers/trash${pageQuery(params)}`
+    );
+  }
+
+  // Only the owner can answer; everyone who prayed is notified
+  async answerPrayerRequest(
+    id: string,
+    testimony?: string
+  ): Promise<PrayerRequest> {
+    return this.request<PrayerRequest>(`/prayers/${id}/answer`, {
+      method: "POST",
+      body: JSON.stringify({ testimony }),
+    });
+  }
+
+  async getAnsweredPrayers(
+    category?: string,
+    params?: PageParams
+  ): Promise<Page<PrayerRequest>> {
+    const qs = pageQuery(params);
+    const filter = category ? `category=${encodeURIComponent(category)}` : "";
+    return this.request<Page<PrayerRequest>>(
+      `/prayers/answered${qs}${filter ? (qs ? "&" : "?") + filter : ""}`
+    );
+  }
+
+  async incrementPrayCount(id: string): Promise<PrayResult> {
+    return this.request<PrayResult>(`/prayers/${id}/pray`, {
+      method: "POST",
+    });
+  }
+
+  async hasPrayed(id: string): Promise<{ prayed: boolean }> {
+    return this.request<{ prayed: boolean }>(`/prayers/${id}/prayed`);
+  }
+
+  async getReactions(id: string): Promise<ReactionStatus> {
+    return this.request<ReactionStatus>(`/prayers/${id}/reactions`);
+  }
+
+  async addReaction(id: string, type: ReactionType): Promise<ReactionResult> {
+    return this.request<ReactionResult>(`/prayers/${id}/reactions`, {
+      method: "POST",
+      body: JSON.stringify({ type }),
+    });
+  }
+
+  async removeReaction(
+    id: string,
+    type: ReactionType
+  ): Promise<ReactionResult> {
+    return this.request<ReactionResult>(`/prayers/${id}/reactions/${type}`, {
+      method: "DELETE",
+    });
+  }
+
+  async savePrayerRequest(id: string): Promise<SaveResult> {
+    return this.request<SaveResult>(`/prayers/${id}/save`, {
+      method: "POST",
+    });
+  }
+
+  async unsavePrayerRequest(id: string): Promise<SaveResult> {
+    return this.request<SaveResult>(`/prayers/${id}/save`, {
+      method: "DELETE",
+    });
+  }
+
+  async reportPrayerRequest(
+    id: string,
+    reason: ReportReason,
+    details?: string
+  ): Promise<ReportResult> {
+    return this.request<ReportResult>(`/prayers/${id}/report`, {
+      method: "POST",
+      body: JSON.stringify({ reason, details }),
+    });
+  }
+
+  async getSavedPrayers(
+    userId: string,
+    params?: PageParams
+  ): Promise<Page<SavedPrayerRequest>> {
+    return this.request<Page<SavedPrayerRequest>>(
+      `/users/${userId}/saved${pageQuery(params)}`
+    );
+  }
+
+  // Administrators only
+  async updateUserRole(userId: string, role: UserRole): Promise<User> {
+    return this.request<User>(`/users/${userId}/role`, {
+      method: "PUT",
+      body: JSON.stringify({ role }),
+    });
+  }
+
+  // Query syntax: words match any term, "quoted phrases" must match and
+  // -words exclude results. Results are ordered by relevance.
+  async searchPrayerRequests(
+    query: string,
+    params?: PageParams & SearchFilters
+  ): Promise<Page<SearchResult>> {
+    const search = new URLSearchParams({ q: query });
+    if (params?.category) search.set("category", params.category);
+    if (params?.priority) search.set("priority", params.priority);
+    if (params?.is_answered !== undefined)
+      search.set("is_answered", String(params.is_answered));
+    if (params?.include_archived) search.set("include_archived", "true");
+    const qs = pageQuery(params).replace("?", "&");
+    return this.request<Page<SearchResult>>(
+      `/prayers/search?${search.toString()}${qs}`
+    );
+  }
+
+  async getPrayersByCategory(
+    category: string,
+    params?: PageParams
+  ): Promise<Page<PrayerRequest>> {
+    return this.request<Page<PrayerRequest>>(
+      `/prayers/category/${category}${pageQuery(params)}`
+    );
   }
 
   async getRecentPrayers(limit: number = 10): Promise<PrayerRequest[]> {
@@ -133,17 +625,180 @@ class ApiService {
     return this.request<PrayerStats>("/prayers/stats");
   }
 
-  // Comment API methods
-  async getComments(prayerId: string): Promise<any[]> {
-    return this.request<any[]>(`/prayers/${prayerId}/comments`);
+  // Live updates via Server-Sent Events. EventSource reconnects on its own
+  // and resumes from the last event it saw.
+  streamPrayers(
+    onEvent: (type: string, data: any) => void,
+    categories: string[] = []
+  ): () => void {
+    const qs = categories.length
+      ? `?category=${encodeURIComponent(categories.join(","))}`
+      : "";
+    const source = new EventSource(`${this.baseUrl}/prayers/stream${qs}`);
+    const types = [
+      "prayer_created",
+      "pray_count_increased",
+      "comment_added",
+      "prayer_answered",
+    ];
+    for (const type of types) {
+      source.addEventListener(type, (e) =>
+        onEvent(type, JSON.parse((e as MessageEvent).data))
+      );
+    }
+    return () => source.close();
   }
 
-  async addComment(prayerId: string, data: any): Promise<any> {
-    return this.request<any>(`/prayers/${prayerId}/comments`, {
+  // Pilgrimage trip API methods
+  async getTrips(
+    params?: PageParams,
+    filters?: TripFilters
+  ): Promise<Page<Trip>> {
+    const filter = filterQuery(filters);
+    const qs = pageQuery(params);
+    return this.request<Page<Trip>>(
+      `/trips${qs}${filter ? (qs ? "&" : "?") + filter : ""}`
+    );
+  }
+
+  async getTrip(id: string): Promise<Trip> {
+    return this.request<Trip>(`/trips/${id}`);
+  }
+
+  async createTrip(data: CreateTripInput): Promise<Trip> {
+    return this.request<Trip>("/trips", {
+      method: "POST",
+      body: JSON.stringify(data),
+    });
+  }
+
+  async updateTrip(id: string, data: Partial<CreateTripInput>): Promise<Trip> {
+    return this.request<Trip>(`/trips/${id}`, {
+      method: "PUT",
+      body: JSON.stringify(data),
+    });
+  }
+
+  async deleteTrip(id: string): Promise<void> {
+    return this.request<void>(`/trips/${id}`, {
+      method: "DELETE",
+    });
+  }
+
+  async addTripRequest(
+    tripId: string,
+    data: { message: string; user_name?: string; is_anonymous?: boolean }
+  ): Promise<TripRequest> {
+    return this.request<TripRequest>(`/trips/${tripId}/requests`, {
+      method: "POST",
+      body: JSON.stringify(data),
+    });
+  }
+
+  // The traveller's checklist
+  async getTripRequests(
+    tripId: string,
+    status?: TripRequest["status"],
+    params?: PageParams
+  ): Promise<Page<TripRequest>> {
+    const qs = pageQuery(params);
+    const filter = status ? `status=${status}` : "";
+    return this.request<Page<TripRequest>>(
+      `/trips/${tripId}/requests${qs}${filter ? (qs ? "&" : "?") + filter : ""}`
+    );
+  }
+
+  async markTripRequestPrayed(
+    tripId: string,
+    requestId: string,
+    place: string
+  ): Promise<TripRequest> {
+    return this.request<TripRequest>(
+      `/trips/${tripId}/requests/${requestId}/prayed`,
+      {
+        method: "POST",
+        body: JSON.stringify({ place }),
+      }
+    );
+  }
+
+  // Notification API methods
+  async getNotifications(
+    unreadOnly = false,
+    params?: PageParams
+  ): Promise<Page<PrayerNotification>> {
+    const qs = pageQuery(params);
+    const filter = unreadOnly ? "unread=true" : "";
+    return this.request<Page<PrayerNotification>>(
+      `/notifications${qs}${filter ? (qs ? "&" : "?") + filter : ""}`
+    );
+  }
+
+  async markNotificationRead(id: string): Promise<void> {
+    return this.request<void>(`/notifications/${id}/read`, {
+      method: "POST",
+    });
+  }
+
+  async markAllNotificationsRead(): Promise<{ marked: number }> {
+    return this.request<{ marked: number }>("/notifications/read", {
+      method: "POST",
+    });
+  }
+
+  // Comment API methods. The tree view pages through top-level comments
+  // with their replies nested.
+  async getComments(
+    prayerId: string,
+    params?: PageParams & { view?: "flat" | "tree" }
+  ): Promise<Page<PrayerComment>> {
+    const qs = pageQuery(params);
+    const view = params?.view ? `view=${params.view}` : "";
+    return this.request<Page<PrayerComment>>(
+      `/prayers/${prayerId}/comments${qs}${view ? (qs ? "&" : "?") + view : ""}`
+    );
+  }
+
+  async addComment(
+    prayerId: string,
+    data: CreateCommentInput
+  ): Promise<PrayerComment> {
+    return this.request<PrayerComment>(`/prayers/${prayerId}/comments`, {
       method: "POST",
       body: JSON.stringify(data),
     });
   }
+
+  async updateComment(
+    prayerId: string,
+    commentId: string,
+    message: string
+  ): Promise<PrayerComment> {
+    return this.request<PrayerComment>(
+      `/prayers/${prayerId}/comments/${commentId}`,
+      {
+        method: "PUT",
+        body: JSON.stringify({ message }),
+      }
+    );
+  }
+
+  async deleteComment(prayerId: string, commentId: string): Promise<void> {
+    return this.request<void>(`/prayers/${prayerId}/comments/${commentId}`, {
+      method: "DELETE",
+    });
+  }
+
+  async reportComment(
+    id: string,
+    reason: ReportReason,
+    details?: string
+  ): Promise<ReportResult> {
+    return this.request<ReportResult>(`/comments/${id}/report`, {
+      method: "POST",
+      body: JSON.stringify({ reason, details }),
+    });
+  }
 }
 
 // Create and export API instance
@@ -156,7 +811,7 @@ export function transformPrayerRequest(backendData: PrayerRequest): any {
     name: backendData.is_anonymous ? "" : backendData.user_name,
     request: backendData.description,
     title: backendData.title,
-    location: "", // Not in backend model yet
+    location: formatLocation(backendData.location),
     createdAt: new Date(backendData.created_at),
     prayedFor: backendData.pray_count,
     isUrgent: backendData.priority === "urgent",
@@ -180,5 +835,15 @@ export function transformToBackendFormat(
     priority: frontendData.isUrgent ? "urgent" : "medium",
     category: frontendData.category || "other",
     tags: frontendData.tags || [],
+    location: frontendData.location?.trim()
+      ? { place: frontendData.location.trim() }
+      : undefined,
   };
 }
+
+// Display form of a location, preferring the holy site's name
+function formatLocation(location?: PrayerLocation): string {
+  if (!location) return "";
+  if (location.holy_site) return HOLY_SITE_NAMES[location.holy_site];
+  return location.place;
+}